@@ -2,6 +2,7 @@ package goent
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/azhai/goent/model"
 )
@@ -20,6 +21,9 @@ func NewSelectFunc[T, R any](state *StateWhere, table *Table[T], col, fun string
 		s.builder.core.Limit = state.builder.core.Limit
 		s.builder.Offset = state.builder.Offset
 		s.builder.RollUp = state.builder.RollUp
+		s.builder.trashed = state.builder.trashed
+		s.builder.ctes = state.builder.ctes
+		s.builder.fromCTE = state.builder.fromCTE
 		s.conn = state.conn
 	}
 	s.builder.VisitFields = []*Field{
@@ -38,17 +42,117 @@ func aggFloat[T any](state *StateWhere, table *Table[T], col, fun string) (float
 	return FetchSingleResult(query)
 }
 
+// aggAvg scans the average as a float, because AVG is not an integer, and truncates it.
+func aggAvg[T any](state *StateWhere, table *Table[T], col string) (int64, error) {
+	avg, err := aggFloat(state, table, col, "AVG(%s)")
+	return int64(avg), err
+}
+
 func aggStr[T any](state *StateWhere, table *Table[T], col, fun string) ([]string, error) {
 	query := NewSelectFunc[T, ResultStr](state, table, col, fun)
 	return FetchArrayResult(query)
 }
 
-// queryIDsByPK queries primary key IDs matching the given condition.
-// It creates a SELECT query for just the PK column and returns the IDs.
-// Returns ErrNoPrimaryKey for tables without a single integer primary key.
-// When limit > 0, the ID query is limited to that many rows.
-func queryIDsByPK[T any](table *Table[T], where Condition, ctx context.Context,
-	conn model.Connection, limit int) ([]int64, error) {
+// groupRow is a row of a grouped count, the key is NULL for the rows without a value.
+type groupRow[K any] struct {
+	Key   *K
+	Count int64
+}
+
+func groupCount[K comparable, T any](state *StateWhere, table *Table[T], col string) (map[K]int64, error) {
+	if table.ColumnInfo(col) == nil {
+		return nil, model.NewColumnNotFoundError(col)
+	}
+	grp := table.Field(col)
+	query := NewSelectFunc[T, groupRow[K]](state, table, "*", "COUNT(%s)")
+	query.builder.VisitFields = []*Field{grp, CountRows()}
+	query.builder.Groups = []*Group{{Field: grp}}
+	query.builder.Orders, query.builder.RollUp = nil, ""
+	query.builder.core.Limit, query.builder.Offset = 0, 0
+	res := make(map[K]int64)
+	for row, err := range query.IterRows(nil) {
+		if err != nil {
+			return nil, err
+		}
+		if row.Key != nil {
+			res[*row.Key] = row.Count
+		}
+	}
+	return res, nil
+}
+
+// GroupCountBy counts the rows of the query for each value of the column, in a single GROUP BY query.
+// The rows whose column is NULL are not counted.
+//
+// Example:
+//
+//	counts, err := goent.GroupCountBy[string](db.Order.Select().Filter(cond), "status")
+func GroupCountBy[K comparable, T, R any](s *StateSelect[T, R], col string) (map[K]int64, error) {
+	return groupCount[K](s.StateWhere, s.table, col)
+}
+
+// GroupCount counts the rows of the query for each value of an integer column,
+// use GroupCountBy for other key types.
+func (s *StateSelect[T, R]) GroupCount(col string) (map[int64]int64, error) {
+	return groupCount[int64](s.StateWhere, s.table, col)
+}
+
+// GroupCount counts the rows of the table for each value of an integer column.
+func (t *Table[T]) GroupCount(col string) (map[int64]int64, error) {
+	return groupCount[int64](nil, t, col)
+}
+
+// CountRows is the COUNT(*) aggregate, to select with SelectAs or compare in Having.
+func CountRows() *Field {
+	return &Field{ColumnName: "*", FieldId: -1, Function: "COUNT(%s)"}
+}
+
+// Count is the COUNT aggregate of the field, the number of rows where it is not NULL.
+func Count(field *Field) *Field {
+	return field.Func("COUNT(%s)", "")
+}
+
+// CountDistinct is the number of distinct values of the field.
+func CountDistinct(field *Field) *Field {
+	return field.Func("COUNT(DISTINCT %s)", "")
+}
+
+// Sum is the SUM aggregate of the field. Scan it into an integer for integer columns,
+// or into a float or a decimal type implementing sql.Scanner for numeric columns.
+//
+// Example:
+//
+//	stats, err := goent.SelectAs[struct {
+//		Status  string
+//		Orders  int64
+//		Total   decimal.Decimal
+//		Average float64
+//	}](db.Order, "status", goent.CountRows(), goent.Sum(amount), goent.Avg(amount)).GroupBy("status").All()
+func Sum(field *Field) *Field {
+	return field.Func("SUM(%s)", "")
+}
+
+// Min is the MIN aggregate of the field.
+func Min(field *Field) *Field {
+	return field.Func("MIN(%s)", "")
+}
+
+// Max is the MAX aggregate of the field.
+func Max(field *Field) *Field {
+	return field.Func("MAX(%s)", "")
+}
+
+// Avg is the AVG aggregate of the field, scan it into a float or a decimal type.
+func Avg(field *Field) *Field {
+	return field.Func("AVG(%s)", "")
+}
+
+// queryKeysByPK queries primary key values matching the given condition.
+// It creates a SELECT query for just the PK column and returns the keys in their Go type.
+// Returns ErrNoPrimaryKey for tables without a single primary key.
+// When limit > 0, the key query is limited to that many rows.
+func queryKeysByPK[T any](table *Table[T], where Condition, ctx context.Context,
+	conn model.Connection, limit int) ([]any, error) {
 	pkField := table.GetPKField()
 	if pkField == nil {
 		return nil, model.ErrNoPrimaryKey
@@ -59,9 +163,19 @@ func queryIDsByPK[T any](table *Table[T], where Condition, ctx context.Context,
 	if limit > 0 {
 		state.builder.core.Limit = limit
 	}
-	sel := NewStateSelectFrom[T, ResultLong](state, table)
+	sel := NewStateSelectFrom[T, T](state, table)
 	sel.builder.VisitFields = []*Field{pkField}
-	return FetchArrayResult(sel)
+	to := func(target any) []any {
+		return []any{reflect.ValueOf(target).Elem().Field(pkField.FieldId).Addr().Interface()}
+	}
+	keys := make([]any, 0)
+	for obj, err := range sel.IterRows(to) {
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, reflect.ValueOf(obj).Elem().Field(pkField.FieldId).Interface())
+	}
+	return keys, nil
 }
 
 func (s *StateSelect[T, R]) Count(col string) (int64, error) {
@@ -96,12 +210,14 @@ func (t *Table[T]) Sum(col string) (int64, error) {
 	return aggInt(nil, t, col, "SUM(%s)")
 }
 
+// Avg returns the average of the column truncated to an integer, use AvgFloat for the exact value.
 func (s *StateSelect[T, R]) Avg(col string) (int64, error) {
-	return aggInt(s.StateWhere, s.table, col, "AVG(%s)")
+	return aggAvg(s.StateWhere, s.table, col)
 }
 
+// Avg returns the average of the column truncated to an integer, use AvgFloat for the exact value.
 func (t *Table[T]) Avg(col string) (int64, error) {
-	return aggInt(nil, t, col, "AVG(%s)")
+	return aggAvg(nil, t, col)
 }
 
 func (s *StateSelect[T, R]) MaxFloat(col string) (float64, error) {
@@ -0,0 +1,77 @@
+package goent
+
+import "reflect"
+
+// isArrayType returns true for a slice of a basic type other than []byte, which is an array column:
+// a native array on PostgreSQL and a JSON array on SQLite.
+func isArrayType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// arrayDest scans an array column, natively on PostgreSQL and from JSON on SQLite.
+type arrayDest struct {
+	jsonDest
+}
+
+// NativeDest implements the model.NativeDest interface.
+func (d *arrayDest) NativeDest() any {
+	return d.ptr
+}
+
+// ScanArray returns a scan destination of an array column into the slice ptr points to,
+// it is used by the generated ScanDest methods for slice fields.
+func ScanArray(ptr any) any {
+	return &arrayDest{jsonDest{ptr: ptr}}
+}
+
+// arrayValue passes a whole slice as a single query argument, instead of a list of values.
+func arrayValue(values any) *Value {
+	return &Value{single: values, Length: 1}
+}
+
+// ArrayContains creates a condition that checks if the array column contains all the values,
+// the @> operator on PostgreSQL.
+//
+// Example:
+//
+//	cond := goent.ArrayContains(db.Product.Field("tags"), []string{"sale", "new"})
+func ArrayContains(field *Field, values any) Condition {
+	if field.isPostgres() {
+		return Condition{Template: "%s @> ?", Fields: []*Field{field}, Values: []*Value{arrayValue(values)}}
+	}
+	return JSONContains(field, values)
+}
+
+// ArrayOverlaps creates a condition that checks if the array column has any of the values,
+// the && operator on PostgreSQL.
+func ArrayOverlaps(field *Field, values any) Condition {
+	if field.isPostgres() {
+		return Condition{Template: "%s && ?", Fields: []*Field{field}, Values: []*Value{arrayValue(values)}}
+	}
+	return Condition{Template: "EXISTS (SELECT 1 FROM json_each(%s) WHERE json_each.value IN (SELECT value FROM json_each(?)))",
+		Fields: []*Field{field}, Values: []*Value{NewValue(jsonArg(values))}}
+}
+
+// Any creates a condition that checks if the array column has the value, value = ANY(column) on PostgreSQL.
+//
+// Example:
+//
+//	cond := goent.Any(db.Product.Field("tags"), "sale")
+func Any(field *Field, value any) Condition {
+	if field.isPostgres() {
+		return Condition{Template: "? = ANY(%s)", Fields: []*Field{field}, Values: []*Value{NewValue(value)}}
+	}
+	return JSONContains(field, []any{value})
+}
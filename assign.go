@@ -0,0 +1,149 @@
+package goent
+
+// CaseWhen is a CASE expression which picks the value of the first true condition,
+// assigned to a column with SetCase.
+//
+// Example:
+//
+//	score := db.Player.Field("score")
+//	level := goent.Case().
+//	    When(goent.GreaterEquals(score, 100), "gold").
+//	    When(goent.GreaterEquals(score, 50), "silver").
+//	    Else("bronze")
+//	err := db.Player.Update().SetCase("level", level).Exec()
+type CaseWhen struct {
+	branches []Condition
+	orElse   *Value
+}
+
+// Case starts a CASE expression.
+func Case() *CaseWhen {
+	return &CaseWhen{}
+}
+
+// When adds a branch which gives the value when the condition is true.
+// The value may be a *Field to take the value of another column.
+func (c *CaseWhen) When(cond Condition, value any) *CaseWhen {
+	then := assignValue(value)
+	c.branches = append(c.branches, Condition{
+		Template: "WHEN " + cond.Template + " THEN " + then.Template,
+		Fields:   append(append([]*Field{}, cond.Fields...), then.Fields...),
+		Values:   append(append([]*Value{}, cond.Values...), then.Values...),
+	})
+	return c
+}
+
+// Else sets the value when no condition is true, without it the value is NULL.
+func (c *CaseWhen) Else(value any) *CaseWhen {
+	c.orElse = NewValue(value)
+	return c
+}
+
+// Expr returns the CASE expression as a condition template for the SET clause.
+func (c *CaseWhen) Expr() Condition {
+	expr := Condition{Template: "CASE"}
+	for _, br := range c.branches {
+		expr.Template += " " + br.Template
+		expr.Fields = append(expr.Fields, br.Fields...)
+		expr.Values = append(expr.Values, br.Values...)
+	}
+	if c.orElse != nil {
+		expr.Template += " ELSE ?"
+		expr.Values = append(expr.Values, c.orElse)
+	}
+	expr.Template += " END"
+	return expr
+}
+
+// assignValue returns the template of a value of CASE, a column for a *Field or a parameter.
+func assignValue(value any) Condition {
+	if fld, ok := value.(*Field); ok {
+		return Condition{Template: "%s", Fields: []*Field{fld}}
+	}
+	return Condition{Template: "?", Values: []*Value{NewValue(value)}}
+}
+
+// incrExpr returns the expression which adds n to the column.
+func incrExpr(fld *Field, n any) Condition {
+	return Condition{Template: "%s + ?", Fields: []*Field{fld}, Values: []*Value{NewValue(n)}}
+}
+
+// Incr adds n to the column, a negative n decrements it, in a single atomic UPDATE.
+// The field is a column name, a *Field or a Col.
+//
+// Example:
+//
+//	err := db.Product.Update().Incr("stock", -1).ByPK(productID)
+func (s *StateUpdate[T]) Incr(field any, n any) *StateUpdate[T] {
+	if fld, ok := s.table.resolveField(field); ok {
+		s.builder.Changes[fld] = incrExpr(fld, n)
+	}
+	return s
+}
+
+// SetExpr sets the column to a SQL expression, the ? placeholders of the expression take the args.
+//
+// Example:
+//
+//	err := db.Player.Update().SetExpr("score", "score * ?", 2).Filter(cond).Exec()
+func (s *StateUpdate[T]) SetExpr(field any, expr string, args ...any) *StateUpdate[T] {
+	if fld, ok := s.table.resolveField(field); ok {
+		s.builder.Changes[fld] = Expr(expr, args...)
+	}
+	return s
+}
+
+// SetField sets the column to the value of another column, which may belong to a joined table.
+//
+// Example:
+//
+//	err := db.Order.Update().Join(model.InnerJoin, db.Customer.TableInfo, on).
+//	    SetField("region", db.Customer.Field("region")).Exec()
+func (s *StateUpdate[T]) SetField(field any, other *Field) *StateUpdate[T] {
+	if fld, ok := s.table.resolveField(field); ok {
+		s.builder.Changes[fld] = other
+	}
+	return s
+}
+
+// SetCase sets the column to the value of a CASE expression.
+func (s *StateUpdate[T]) SetCase(field any, c *CaseWhen) *StateUpdate[T] {
+	if fld, ok := s.table.resolveField(field); ok {
+		s.builder.Changes[fld] = c.Expr()
+	}
+	return s
+}
+
+// setChange records a change of the column given as a name, a *Field or a Col.
+func (s *StateUpdateByID[T]) setChange(field any, value any) *StateUpdateByID[T] {
+	if fld, ok := s.table.resolveField(field); ok {
+		if s.changes == nil {
+			s.changes = make(Dict)
+		}
+		s.changes[fld.ColumnName] = value
+	}
+	return s
+}
+
+// Incr adds n to the column of the matched rows, like StateUpdate.Incr.
+func (s *StateUpdateByID[T]) Incr(field any, n any) *StateUpdateByID[T] {
+	if fld, ok := s.table.resolveField(field); ok {
+		return s.setChange(fld, incrExpr(fld, n))
+	}
+	return s
+}
+
+// SetExpr sets the column of the matched rows to a SQL expression, like StateUpdate.SetExpr.
+func (s *StateUpdateByID[T]) SetExpr(field any, expr string, args ...any) *StateUpdateByID[T] {
+	return s.setChange(field, Expr(expr, args...))
+}
+
+// SetField sets the column of the matched rows to the value of another column of the row.
+func (s *StateUpdateByID[T]) SetField(field any, other *Field) *StateUpdateByID[T] {
+	return s.setChange(field, other)
+}
+
+// SetCase sets the column of the matched rows to the value of a CASE expression.
+func (s *StateUpdateByID[T]) SetCase(field any, c *CaseWhen) *StateUpdateByID[T] {
+	return s.setChange(field, c.Expr())
+}
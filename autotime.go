@@ -0,0 +1,136 @@
+package goent
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/azhai/goent/utils"
+)
+
+// autoTimeUnit is the unit of an integer auto time column.
+type autoTimeUnit uint8
+
+const (
+	autoTimeSecond autoTimeUnit = iota // unix seconds (default)
+	autoTimeMilli                      // unix milliseconds
+)
+
+// autoTime is a column filled with the current time on insert, and also on update for update times.
+type autoTime struct {
+	column   *Column
+	onUpdate bool         // true for auto_update_time, false for auto_create_time
+	unit     autoTimeUnit // unit of integer columns, time columns ignore it
+}
+
+// addAutoTime registers a column tagged with auto_create_time or auto_update_time.
+// The tag value "milli" stores unix milliseconds in an integer column, e.g. goe:"auto_update_time:milli".
+// Supported types are time.Time, *time.Time and integers, the tag is ignored on other types.
+func (info *TableInfo) addAutoTime(column *Column, typeOf reflect.Type, tag string) {
+	for _, key := range []string{"auto_create_time", "auto_update_time"} {
+		value, ok := utils.GetTagValue(tag, key)
+		if !ok && !utils.HasTagValue(tag, key) {
+			continue
+		}
+		if !isTimeType(typeOf) && !isIntegerKind(typeOf.Kind()) {
+			return
+		}
+		at := &autoTime{column: column, onUpdate: key == "auto_update_time"}
+		if value == "milli" {
+			at.unit = autoTimeMilli
+		}
+		info.autoTimes = append(info.autoTimes, at)
+		return
+	}
+}
+
+// isTimeType returns true for time.Time and *time.Time.
+func isTimeType(typeOf reflect.Type) bool {
+	if typeOf.Kind() == reflect.Pointer {
+		typeOf = typeOf.Elem()
+	}
+	return typeOf == reflect.TypeFor[time.Time]()
+}
+
+// isIntegerKind returns true for the signed and unsigned integer kinds.
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// value returns the current time in the representation of the column.
+func (at *autoTime) value(now time.Time, typeOf reflect.Type) any {
+	if isTimeType(typeOf) {
+		return now
+	}
+	if at.unit == autoTimeMilli {
+		return now.UnixMilli()
+	}
+	return now.Unix()
+}
+
+// set writes the current time to the field of a row.
+func (at *autoTime) set(fieldOf reflect.Value, now time.Time) {
+	switch {
+	case fieldOf.Kind() == reflect.Pointer:
+		fieldOf.Set(reflect.ValueOf(&now))
+	case fieldOf.Kind() == reflect.Struct:
+		fieldOf.Set(reflect.ValueOf(now))
+	case fieldOf.CanInt():
+		fieldOf.SetInt(at.value(now, fieldOf.Type()).(int64))
+	case fieldOf.CanUint():
+		fieldOf.SetUint(uint64(at.value(now, fieldOf.Type()).(int64)))
+	}
+}
+
+// fillAutoTimes fills the auto time fields of a row before it is written.
+// On insert the create and update times are only filled when they are zero,
+// on update the update times are always set. It returns the filled columns.
+func (info *TableInfo) fillAutoTimes(valueOf reflect.Value, isUpdate bool) []*Column {
+	if len(info.autoTimes) == 0 {
+		return nil
+	}
+	var filled []*Column
+	now := time.Now()
+	for _, at := range info.autoTimes {
+		fieldOf := valueOf.Field(at.column.FieldId)
+		if isUpdate && !at.onUpdate || !isUpdate && !fieldOf.IsZero() {
+			continue
+		}
+		at.set(fieldOf, now)
+		filled = append(filled, at.column)
+	}
+	return filled
+}
+
+// touchChanges adds the update times to the changes of an UPDATE,
+// unless the caller already sets the column.
+func (info *TableInfo) touchChanges(changes map[*Field]any, isInsert bool) {
+	if len(info.autoTimes) == 0 || len(changes) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, at := range info.autoTimes {
+		if !at.onUpdate && !isInsert {
+			continue
+		}
+		if hasChange(changes, info.TableAddr, at.column.ColumnName) {
+			continue
+		}
+		typeOf := info.modelType.Field(at.column.FieldId).Type
+		changes[info.sortedFields[at.column.FieldId]] = at.value(now, typeOf)
+	}
+}
+
+// hasChange returns true if the changes already contain the column of the table.
+func hasChange(changes map[*Field]any, tableAddr uintptr, columnName string) bool {
+	for fld := range changes {
+		if fld.ColumnName == columnName && (fld.TableAddr == tableAddr || fld.TableAddr == 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -192,12 +192,21 @@ type Builder struct {
 	Groups []*Group // GROUP BY clauses
 	Offset int      // OFFSET clause value
 
-	Returning string // RETURNING clause for INSERT/UPDATE operations
-	RollUp    string // ROLL UP clause for GROUP BY operations
-	ForUpdate bool   // FOR UPDATE clause for transactional operations
-
-	cachedSortedChanges []*Field // Cached sorted changes to avoid re-sorting
-	visitFieldsShared   bool     // Whether VisitFields is shared (needs clone before append)
+	Returning string          // RETURNING clause for INSERT/UPDATE operations
+	Conflict  *conflictClause // ON CONFLICT clause for upsert operations
+	RollUp    string          // ROLL UP clause for GROUP BY operations
+	ForUpdate bool            // FOR UPDATE clause, locking the selected rows until the transaction ends
+	ForShare  bool            // FOR SHARE clause, locking the selected rows against updates
+
+	cachedSortedChanges []*Field    // Cached sorted changes to avoid re-sorting
+	visitFieldsShared   bool        // Whether VisitFields is shared (needs clone before append)
+	softDelete          *TableInfo  // Table whose soft delete scope is added to SELECT queries
+	trashed             trashedMode // Which soft deleted rows a SELECT returns
+	ctes                []*CTE      // CTEs declared in the WITH clause of a SELECT
+	fromCTE             *CTE        // CTE selected instead of the table, aliased with the table name
+	compounds           []compound  // SELECTs combined with UNION, INTERSECT or EXCEPT
+	lockOf              []string    // tables locked by FOR UPDATE or FOR SHARE, all when empty
+	lockWait            string      // NOWAIT or SKIP LOCKED after FOR UPDATE or FOR SHARE
 
 	core BuilderCore // Shared core fields (composition, not embedding)
 }
@@ -247,6 +256,11 @@ func (b *Builder) Reset() {
 	b.Offset = 0
 	b.RollUp = ""
 	b.visitFieldsShared = false
+	b.softDelete = nil
+	b.trashed = trashedExclude
+	b.ctes = nil
+	b.fromCTE = nil
+	b.compounds = nil
 	b.core.resetBuf()
 }
 
@@ -259,7 +273,11 @@ func (b *Builder) ResetForSave() {
 	b.visitFieldsShared = false
 	b.core.Limit = -1
 	b.Returning = ""
+	b.Conflict = nil
 	b.ForUpdate = false
+	b.ForShare = false
+	b.lockOf = nil
+	b.lockWait = ""
 	b.core.argNo = 0
 	b.core.resetHolders()
 	b.cachedSortedChanges = nil
@@ -301,6 +319,31 @@ func (b *Builder) SetTable(table *TableInfo) *Builder {
 	c := &b.core
 	c.Table = table.Table()
 	c.fullName = table.GetFormattedName()
+	b.softDelete = nil
+	if table.HasSoftDelete() {
+		b.softDelete = table
+	}
+	return b
+}
+
+// SetLockTables sets the tables of FOR UPDATE OF or FOR SHARE OF, all the tables when none is given.
+// PostgreSQL references them by the unqualified name of the FROM clause, escaped by the driver.
+func (b *Builder) SetLockTables(tables ...*TableInfo) *Builder {
+	b.lockOf = b.lockOf[:0]
+	for _, info := range tables {
+		name := info.TableName
+		if info.driver != nil {
+			name = info.driver.KeywordHandler(name)
+		}
+		b.lockOf = append(b.lockOf, name)
+	}
+	return b
+}
+
+// SetLockWait sets what a locking clause does with the rows locked by other transactions:
+// "NOWAIT", "SKIP LOCKED" or an empty string to wait for them.
+func (b *Builder) SetLockWait(wait string) *Builder {
+	b.lockWait = wait
 	return b
 }
 
@@ -321,21 +364,28 @@ func (b *Builder) buildHead() []any {
 		c.buf.WriteString("SELECT ")
 		if len(b.VisitFields) == 0 {
 			c.buf.WriteString("*")
-		} else if b.Type == model.SelectJoinQuery {
-			c.buf.WriteString(b.VisitFields[0].String())
-			for _, f := range b.VisitFields[1:] {
-				c.buf.WriteByte(',')
-				c.buf.WriteString(f.String())
-			}
 		} else {
-			c.buf.WriteString(b.VisitFields[0].Simple())
-			for _, f := range b.VisitFields[1:] {
-				c.buf.WriteByte(',')
-				c.buf.WriteString(f.Simple())
+			for i, f := range b.VisitFields {
+				if i > 0 {
+					c.buf.WriteByte(',')
+				}
+				if b.Type == model.SelectJoinQuery {
+					c.buf.WriteString(f.String())
+				} else {
+					c.buf.WriteString(f.Simple())
+				}
+				if f.AliasName != "" {
+					c.buf.WriteString(" AS ")
+					c.buf.WriteString(f.AliasName)
+				}
 			}
 		}
 		c.buf.WriteString(" FROM ")
-		if c.fullName != "" {
+		if b.fromCTE != nil && c.Table != nil {
+			c.buf.WriteString(b.fromCTE.Name)
+			c.buf.WriteString(" AS ")
+			c.buf.WriteString(c.Table.Name)
+		} else if c.fullName != "" {
 			c.buf.WriteString(c.fullName)
 		} else if c.Table != nil && c.Table.Name != "" {
 			c.buf.WriteString(c.Table.Name)
@@ -367,7 +417,7 @@ func (b *Builder) buildHead() []any {
 			} else {
 				c.holders = append(c.holders, "$"+strconv.Itoa(c.argNo))
 			}
-			args = append(args, v)
+			args = append(args, f.arg(v))
 			c.buf.WriteString(f.Simple())
 		}
 	case model.InsertAllQuery:
@@ -433,42 +483,32 @@ func (b *Builder) buildDoing() []any {
 			}
 		}
 		c.buf.WriteByte(')')
-	case model.UpdateQuery:
+	case model.UpdateQuery, model.UpdateJoinQuery:
 		if len(b.Changes) == 0 {
 			return args
 		}
+		// a JOIN update qualifies the columns of the values, the assigned columns never
+		full := b.Type == model.UpdateJoinQuery
 		c.buf.WriteString(" SET ")
 		for i, f := range b.sortedChanges() {
-			v := b.Changes[f]
-			c.argNo += 1
 			if i > 0 {
 				c.buf.WriteString(", ")
 			}
 			c.buf.WriteString(f.Simple())
 			c.buf.WriteByte('=')
-			c.writeParam(c.argNo)
-			args = append(args, v)
-		}
-	case model.UpdateJoinQuery:
-		if len(b.Changes) == 0 {
-			return args
-		}
-		c.buf.WriteString(" SET ")
-		for i, f := range b.sortedChanges() {
-			v := b.Changes[f]
-			if i > 0 {
-				c.buf.WriteString(", ")
-			}
-			if fld, ok := v.(*Field); ok {
-				c.buf.WriteString(f.Simple())
-				c.buf.WriteByte('=')
-				c.buf.WriteString(fld.String())
-			} else {
+			switch v := b.Changes[f].(type) {
+			case *Field:
+				if full {
+					c.buf.WriteString(v.String())
+				} else {
+					c.buf.WriteString(v.Simple())
+				}
+			case Condition:
+				c.argNo = c.buildTemplate(v, &args, c.argNo, full)
+			default:
 				c.argNo += 1
-				c.buf.WriteString(f.Simple())
-				c.buf.WriteByte('=')
 				c.writeParam(c.argNo)
-				args = append(args, v)
+				args = append(args, f.arg(v))
 			}
 		}
 	}
@@ -491,23 +531,27 @@ func (b *Builder) buildTail() []any {
 
 	if b.Type == model.SelectQuery || b.Type == model.SelectJoinQuery {
 		if len(b.Groups) != 0 {
-			c.buf.WriteByte(' ')
-			gp := b.Groups[0]
-			c.buf.WriteString("GROUP BY ")
-			c.buf.WriteString(gp.String())
-			for _, gp = range b.Groups[1:] {
-				c.buf.WriteByte(',')
-				c.buf.WriteString(gp.String())
+			args = b.buildGroupBy(len(b.Groups))
+			if b.RollUp == rollUpUnion {
+				args = append(args, b.buildRollUpUnion()...)
 			}
 		}
 
+		if len(b.compounds) != 0 {
+			args = append(args, b.buildCompounds()...)
+		}
+
 		if len(b.Orders) != 0 {
 			c.buf.WriteString(" ORDER BY ")
 			for i, ob := range b.Orders {
 				if i > 0 {
 					c.buf.WriteString(", ")
 				}
-				c.buf.WriteString(ob.String())
+				if len(b.compounds) != 0 || b.RollUp == rollUpUnion {
+					c.buf.WriteString(ob.Simple()) // the result columns of a compound select have no table
+				} else {
+					c.buf.WriteString(ob.String())
+				}
 				if ob.Desc {
 					c.buf.WriteString(" DESC")
 				}
@@ -522,8 +566,12 @@ func (b *Builder) buildTail() []any {
 			c.buf.WriteString(" OFFSET ")
 			c.buf.WriteString(strconv.Itoa(b.Offset))
 		}
+		b.buildLock()
 	}
 
+	if b.Conflict != nil && b.IsInsertQuery() {
+		b.Conflict.write(c.buf)
+	}
 	if b.Returning != "" && b.IsInsertQuery() {
 		c.buf.WriteString(" RETURNING ")
 		c.buf.WriteString(b.Returning)
@@ -532,6 +580,89 @@ func (b *Builder) buildTail() []any {
 	return args
 }
 
+// buildLock writes the row locking clause: FOR UPDATE or FOR SHARE, OF tables, NOWAIT or SKIP LOCKED.
+func (b *Builder) buildLock() {
+	c := &b.core
+	if b.ForUpdate {
+		c.buf.WriteString(" FOR UPDATE")
+	} else if b.ForShare {
+		c.buf.WriteString(" FOR SHARE")
+	} else {
+		return
+	}
+	if len(b.lockOf) > 0 {
+		c.buf.WriteString(" OF ")
+		c.buf.WriteString(strings.Join(b.lockOf, ", "))
+	}
+	if b.lockWait != "" {
+		c.buf.WriteByte(' ')
+		c.buf.WriteString(b.lockWait)
+	}
+}
+
+// Values of Builder.RollUp
+const (
+	rollUpGroups = "ROLLUP"    // GROUP BY ROLLUP (...)
+	rollUpUnion  = "UNION ALL" // subtotals selected with UNION ALL, for databases without ROLLUP
+)
+
+// buildGroupBy writes the GROUP BY clause of the first n groups and the HAVING clause.
+func (b *Builder) buildGroupBy(n int) []any {
+	c := &b.core
+	if n > 0 {
+		c.buf.WriteString(" GROUP BY ")
+		if b.RollUp == rollUpGroups {
+			c.buf.WriteString("ROLLUP (")
+		}
+		for i, gp := range b.Groups[:n] {
+			if i > 0 {
+				c.buf.WriteByte(',')
+			}
+			c.buf.WriteString(gp.String())
+		}
+		if b.RollUp == rollUpGroups {
+			c.buf.WriteByte(')')
+		}
+	}
+	having := make([]Condition, 0, len(b.Groups))
+	for _, gp := range b.Groups {
+		having = append(having, gp.Having)
+	}
+	cond := And(having...)
+	if cond.IsEmpty() {
+		return nil
+	}
+	var args []any
+	c.buf.WriteString(" HAVING ")
+	c.argNo = b.buildTemplate(cond, &args, c.argNo, true)
+	return args
+}
+
+// buildRollUpUnion writes the subtotal rows of ROLLUP as SELECTs grouped by fewer columns,
+// combined with UNION ALL. The columns which are no longer grouped are selected as NULL.
+func (b *Builder) buildRollUpUnion() []any {
+	var args []any
+	fields := b.VisitFields
+	defer func() { b.VisitFields = fields }()
+	for n := len(b.Groups) - 1; n >= 0; n-- {
+		b.VisitFields = make([]*Field, len(fields))
+		for i, fld := range fields {
+			b.VisitFields[i] = fld
+			for _, gp := range b.Groups[n:] {
+				if fld.Function == "" && fld.TableAddr == gp.TableAddr && fld.ColumnName == gp.ColumnName {
+					b.VisitFields[i] = &Field{ColumnName: "NULL", FieldId: -1}
+				}
+			}
+		}
+		b.core.buf.WriteString(" UNION ALL ")
+		args = append(args, b.buildHead()...)
+		args = append(args, b.buildJoins()...)
+		args = append(args, b.buildWhere(b.IsJoinQuery())...)
+		args = append(args, b.buildGroupBy(n)...)
+	}
+	return args
+}
+
 // BuildJoins builds the JOIN clauses for the query
 // It processes all join tables and returns the query arguments
 func (b *Builder) buildJoins() []any {
@@ -541,7 +672,11 @@ func (b *Builder) buildJoins() []any {
 	}
 	if b.Type == model.UpdateJoinQuery {
 		c.buf.WriteString(" FROM ")
-		c.buf.WriteString(b.Joins[0].Table.String())
+		if b.Joins[0].fullName != "" {
+			c.buf.WriteString(b.Joins[0].fullName)
+		} else {
+			c.buf.WriteString(b.Joins[0].Table.String())
+		}
 		return nil
 	}
 
@@ -577,6 +712,9 @@ func (c *BuilderCore) writeParam(n int) {
 // appendValueParam writes value parameters to the buffer.
 // Shared by both Builder and DeleteBuilder to avoid code duplication.
 func (c *BuilderCore) appendValueParam(val *Value, startIdx int, args *[]any) int {
+	if val.sub != nil {
+		return c.appendSubquery(val.sub, startIdx, args)
+	}
 	if len(val.Args) > 0 {
 		c.buf.WriteByte('(')
 		for j, arg := range val.Args {
@@ -647,19 +785,27 @@ func (c *BuilderCore) buildTemplate(cond Condition, args *[]any, startIdx int, f
 // BuildWhere builds the WHERE clause for the Builder
 func (b *Builder) buildWhere(full bool) []any {
 	c := &b.core
-	if c.Where.IsEmpty() {
+	where := c.Where
+	// SELECT queries of a soft delete table skip the deleted rows unless asked otherwise
+	if b.softDelete != nil && b.Type <= model.SelectJoinQuery {
+		where = And(where, b.softDelete.softDeleteScope(b.trashed))
+	}
+	if where.IsEmpty() {
 		return nil
 	}
 	var args []any
 	c.buf.WriteString(" WHERE ")
-	c.argNo = b.buildTemplate(c.Where, &args, c.argNo, full)
+	c.argNo = b.buildTemplate(where, &args, c.argNo, full)
 	return args
 }
 
 func (b *Builder) Build(destroy bool) (sql string, args []any) {
 	c := &b.core
 	c.argNo = 0
-	args = b.buildHead()
+	args = b.buildWith()
+	if headArgs := b.buildHead(); len(headArgs) > 0 {
+		args = append(args, headArgs...)
+	}
 	if doArgs := b.buildDoing(); len(doArgs) > 0 {
 		args = append(args, doArgs...)
 	}
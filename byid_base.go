@@ -50,23 +50,23 @@ func (s *byIDBase[T]) OnTransaction(tx model.Transaction) *byIDBase[T] {
 	return s
 }
 
-// queryIDs runs Phase 1: SELECT pk FROM table WHERE <conditions> [LIMIT n].
-// Returns ErrNoPrimaryKey for tables without a single integer primary key.
-func (s *byIDBase[T]) queryIDs() ([]int64, error) {
-	return queryIDsByPK(s.table, s.where, s.ctx, s.conn, s.limit)
+// queryKeys runs Phase 1: SELECT pk FROM table WHERE <conditions> [LIMIT n].
+// Returns ErrNoPrimaryKey for tables without a single primary key.
+func (s *byIDBase[T]) queryKeys() ([]any, error) {
+	return queryKeysByPK(s.table, s.where, s.ctx, s.conn, s.limit)
 }
 
 // buildInBatchCond runs Phase 1 and builds the IN-batched condition for Phase 2.
-// Returns (ids, cond, error). If ids is empty, cond is zero-value and should be skipped.
-func (s *byIDBase[T]) buildInBatchCond() ([]int64, Condition, error) {
-	ids, err := s.queryIDs()
+// Returns (keys, cond, error). If keys is empty, cond is zero-value and should be skipped.
+func (s *byIDBase[T]) buildInBatchCond() ([]any, Condition, error) {
+	keys, err := s.queryKeys()
 	if err != nil {
 		return nil, Condition{}, err
 	}
-	if len(ids) == 0 {
-		return ids, Condition{}, nil
+	if len(keys) == 0 {
+		return keys, Condition{}, nil
 	}
 	pkField := s.table.GetPKField()
-	cond := InBatch(pkField, ids, s.batchSize)
-	return ids, cond, nil
+	cond := InBatch(pkField, keys, s.batchSize)
+	return keys, cond, nil
 }
@@ -3,9 +3,11 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"go/format"
 	"go/types"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/azhai/gobus/log"
@@ -24,8 +26,12 @@ type ModelStruct struct {
 
 // ModelField represents a field in a struct with its name and type.
 type ModelField struct {
-	Name string
-	Type string
+	Name   string
+	Type   string
+	Column string // the column name, goe:"column:..." or the snake case of Name
+	JSON   bool   // tagged with goe:"json"
+	Array  bool   // a slice of a basic type, an array column
+	typ    types.Type
 }
 
 // RunFieldsGeneration generates the fields.go file for a package.
@@ -65,7 +71,8 @@ func generateForPackage(pkg *packages.Package, outputPath string) error {
 		return fmt.Errorf("Error loading abbreviations from %s: %v\n", abbrPath, err)
 	}
 
-	structs := filterModels(findStructs(pkg.Types.Scope()), pkg.Name)
+	allStructs := findStructs(pkg.Types.Scope())
+	structs := filterModels(allStructs, pkg.Name)
 	if len(structs) == 0 {
 		return fmt.Errorf("No models found in package %s\n", pkg.Name)
 	}
@@ -75,17 +82,32 @@ func generateForPackage(pkg *packages.Package, outputPath string) error {
 	fmt.Fprintf(&buf, "//\n")
 	fmt.Fprintf(&buf, "//go:build !ignore_autogenerated\n\n")
 	fmt.Fprintf(&buf, "package %s\n\n", pkg.Name)
-	fmt.Fprintf(&buf, "import \"github.com/azhai/goent\"\n\n")
 
-	success := false
+	var body bytes.Buffer
+	importPkgs := utils.NewImports()
+	importPkgs.AddThirdPackage("\"github.com/azhai/goent\"")
+	qualifier := importQualifier(pkg.Types, importPkgs)
+	generated := make(map[string]bool)
 	for _, st := range structs {
-		if generateStructScanner(&buf, st) {
-			success = true
+		if generateStructScanner(&body, st) {
+			generateStructColumns(&body, st, qualifier)
+			generated[st.Name] = true
 		}
 	}
-	if !success || buf.Len() == 0 {
+	if len(generated) == 0 || body.Len() == 0 {
 		return fmt.Errorf("No scanner methods generated for package %s\n", pkg.Name)
 	}
+	for _, st := range allStructs {
+		if st.Class == "Database" {
+			generateDatabaseColumns(&body, st, generated, pkg.Name)
+		}
+	}
+	importPkgs.WriteTo(&buf)
+	buf.Write(body.Bytes())
+	if src, err := format.Source(buf.Bytes()); err == nil {
+		buf.Reset()
+		buf.Write(src)
+	}
 
 	if err := log.WriteToFile(&buf, outputPath); err != nil {
 		return err
@@ -105,8 +127,14 @@ func generateStructScanner(buf *bytes.Buffer, st *ModelStruct) bool {
 	fmt.Fprintf(buf, "// %s\n", st.Name)
 	fmt.Fprintf(buf, "// ------------------------------\n\n")
 
-	// implement goent.Entity interface
-	if pkIndex >= 0 {
+	// implement goent.Entity interface, or goent.KeyEntity for non-integer primary keys
+	if pkIndex >= 0 && !isIntegerType(fields[pkIndex].Type) {
+		pkField := fields[pkIndex]
+		fmt.Fprintf(buf, "// implement goent.KeyEntity interface GetKey for %s\n", st.Name)
+		fmt.Fprintf(buf, "func (t *%s) GetKey() any {\n", st.Name)
+		fmt.Fprintf(buf, "\treturn t.%s\n", pkField.Name)
+		fmt.Fprintf(buf, "}\n\n")
+	} else if pkIndex >= 0 {
 		pkField := fields[pkIndex]
 		fmt.Fprintf(buf, "// implement goent.Entity interface GetID for %s\n", st.Name)
 		fmt.Fprintf(buf, "func (t *%s) GetID() int64 {\n", st.Name)
@@ -122,7 +150,7 @@ func generateStructScanner(buf *bytes.Buffer, st *ModelStruct) bool {
 		if pkField.Type == "int64" {
 			fmt.Fprintf(buf, "\tt.%s = id\n", pkField.Name)
 		} else {
-			fmt.Fprintf(buf, "\tt.%s = int(id)\n", pkField.Name)
+			fmt.Fprintf(buf, "\tt.%s = %s(id)\n", pkField.Name, pkField.Type)
 		}
 		fmt.Fprintf(buf, "}\n\n")
 	}
@@ -136,7 +164,7 @@ func generateStructScanner(buf *bytes.Buffer, st *ModelStruct) bool {
 			if i == pkIndex {
 				continue
 			}
-			fmt.Fprintf(buf, "\t\t{Key: \"%s\", Value: t.%s},\n", utils.ToSnakeCase(f.Name), f.Name)
+			fmt.Fprintf(buf, "\t\t{Key: \"%s\", Value: t.%s},\n", f.Column, f.Name)
 		}
 		fmt.Fprintf(buf, "\t}\n}\n\n")
 	}
@@ -146,7 +174,13 @@ func generateStructScanner(buf *bytes.Buffer, st *ModelStruct) bool {
 	fmt.Fprintf(buf, "func (t *%s) ScanDest() []any {\n", st.Name)
 	fmt.Fprintf(buf, "\treturn []any{\n")
 	for _, f := range fields {
-		fmt.Fprintf(buf, "\t\t&t.%s,\n", f.Name)
+		if f.JSON {
+			fmt.Fprintf(buf, "\t\tgoent.ScanJSON(&t.%s),\n", f.Name)
+		} else if f.Array {
+			fmt.Fprintf(buf, "\t\tgoent.ScanArray(&t.%s),\n", f.Name)
+		} else {
+			fmt.Fprintf(buf, "\t\t&t.%s,\n", f.Name)
+		}
 	}
 	fmt.Fprintf(buf, "\t}\n}\n\n")
 
@@ -159,6 +193,110 @@ func generateStructScanner(buf *bytes.Buffer, st *ModelStruct) bool {
 	return true
 }
 
+// generateStructColumns generates the typed column references of a struct,
+// a <Name>Columns struct of goent.Col and the <Name>Cols function which binds them to a table.
+func generateStructColumns(buf *bytes.Buffer, st *ModelStruct, qualifier types.Qualifier) {
+	fields, _ := getModelFields(st)
+	fmt.Fprintf(buf, "// %sColumns holds the typed column references of %s.\n", st.Name, st.Name)
+	fmt.Fprintf(buf, "type %sColumns struct {\n", st.Name)
+	for _, f := range fields {
+		fmt.Fprintf(buf, "\t%s goent.Col[%s]\n", f.Name, types.TypeString(f.typ, qualifier))
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// %sCols returns the typed column references of the %s table, built once per table.\n", st.Name, st.Name)
+	fmt.Fprintf(buf, "func %sCols(t *goent.Table[%s]) *%sColumns {\n", st.Name, st.Name, st.Name)
+	fmt.Fprintf(buf, "\treturn goent.TableColumns(t, func(info *goent.TableInfo) *%sColumns {\n", st.Name)
+	fmt.Fprintf(buf, "\t\treturn &%sColumns{\n", st.Name)
+	for _, f := range fields {
+		fmt.Fprintf(buf, "\t\t\t%s: goent.NewCol[%s](info, \"%s\"),\n",
+			f.Name, types.TypeString(f.typ, qualifier), f.Column)
+	}
+	fmt.Fprintf(buf, "\t\t}\n\t})\n}\n\n")
+}
+
+// DatabaseTable is a table field of a database struct, directly or in one of its schema structs.
+type DatabaseTable struct {
+	Name, Path, Model string
+}
+
+// generateDatabaseColumns generates the C method of a database struct,
+// which returns the typed column references of its tables, e.g. db.C().User.Email.
+func generateDatabaseColumns(buf *bytes.Buffer, st *ModelStruct, generated map[string]bool, pkgName string) {
+	tables := findDatabaseTables(st.Struct, "", generated, pkgName, make(map[string]bool))
+	if len(tables) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "// ------------------------------\n")
+	fmt.Fprintf(buf, "// %s\n", st.Name)
+	fmt.Fprintf(buf, "// ------------------------------\n\n")
+
+	fmt.Fprintf(buf, "// %sColumns holds the typed column references of the tables of %s.\n", st.Name, st.Name)
+	fmt.Fprintf(buf, "type %sColumns struct {\n", st.Name)
+	for _, tbl := range tables {
+		fmt.Fprintf(buf, "\t%s *%sColumns\n", tbl.Name, tbl.Model)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, "// C returns the typed column references of the tables of %s.\n", st.Name)
+	fmt.Fprintf(buf, "func (d *%s) C() %sColumns {\n", st.Name, st.Name)
+	fmt.Fprintf(buf, "\treturn %sColumns{\n", st.Name)
+	for _, tbl := range tables {
+		fmt.Fprintf(buf, "\t\t%s: %sCols(d.%s),\n", tbl.Name, tbl.Model, tbl.Path)
+	}
+	fmt.Fprintf(buf, "\t}\n}\n\n")
+}
+
+// findDatabaseTables returns the goent.Table fields of the generated models in a database struct
+// and in its schema structs, a table name found twice is only kept the first time.
+func findDatabaseTables(st *types.Struct, prefix string, generated map[string]bool,
+	pkgName string, seen map[string]bool) (tables []DatabaseTable) {
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+		if model, ok := cutModelName(field.Type().String(), pkgName); ok {
+			if generated[model] && !seen[field.Name()] {
+				seen[field.Name()] = true
+				tables = append(tables, DatabaseTable{Name: field.Name(), Path: prefix + field.Name(), Model: model})
+			}
+			continue
+		}
+		if sub, ok := field.Type().Underlying().(*types.Struct); ok && prefix == "" {
+			tables = append(tables, findDatabaseTables(sub, field.Name()+".", generated, pkgName, seen)...)
+		}
+	}
+	return tables
+}
+
+// importQualifier returns a types.Qualifier which writes the types of other packages
+// with their package name and adds those packages to the imports.
+func importQualifier(current *types.Package, importPkgs *utils.Imports) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == current {
+			return ""
+		}
+		path := strconv.Quote(other.Path())
+		if first, _, _ := strings.Cut(other.Path(), "/"); strings.Contains(first, ".") {
+			importPkgs.AddThirdPackage(path)
+		} else {
+			importPkgs.AddStdPackage(path)
+		}
+		return other.Name()
+	}
+}
+
+// isIntegerType reports whether a Go type name is a builtin integer type.
+func isIntegerType(typeName string) bool {
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
 func filterModels(structs []*ModelStruct, pkgName string) (models []*ModelStruct) {
 	allNames, names := make(map[string]bool), make([]string, 0)
 	for _, st := range structs {
@@ -202,10 +340,12 @@ func getModelFields(st *ModelStruct) ([]ModelField, int) {
 		}
 
 		fieldType := field.Type().String()
-		if strings.HasPrefix(fieldType, "[]") {
+		isJSON := utils.HasTagValue(goeTag, "json")
+		isArray := !isJSON && isArrayType(field.Type())
+		if strings.HasPrefix(fieldType, "[]") && !isJSON && !isArray {
 			continue
 		}
-		if strings.HasPrefix(fieldType, "*") && isTableTypeStruct(field.Type()) {
+		if strings.HasPrefix(fieldType, "*") && isTableTypeStruct(field.Type()) && !isJSON {
 			continue
 		}
 
@@ -221,7 +361,12 @@ func getModelFields(st *ModelStruct) ([]ModelField, int) {
 				idIndex = fieldIdx
 			}
 		}
-		fields = append(fields, ModelField{Name: fieldName, Type: fieldType})
+		columnName := utils.ToSnakeCase(fieldName)
+		if col, ok := utils.GetTagValue(goeTag, "column"); ok && col != "" {
+			columnName = col
+		}
+		fields = append(fields, ModelField{Name: fieldName, Type: fieldType, Column: columnName,
+			JSON: isJSON, Array: isArray, typ: field.Type()})
 	}
 
 	if isComposite {
@@ -350,6 +495,17 @@ func checkStructTypeName(typ types.Type) (*types.Named, bool) {
 	return named, ok
 }
 
+// isArrayType returns true for a slice of a basic type other than []byte, which is an array column.
+func isArrayType(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && basic.Kind() != types.Byte && basic.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 &&
+		basic.Info()&types.IsComplex == 0
+}
+
 // isTableTypeStruct checks if a pointer type points to a table/model struct.
 // Only table types (structs with a TableName method) should be skipped as fields.
 // Standard library structs like *time.Time should NOT be skipped.
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var update = flag.Bool("update", false, "update the generated files in testdata")
+
+// TestFieldsGeneration generates the fields of the fixture package and compares them with
+// testdata/models/fields.go, then checks that the fixture compiles with them.
+func TestFieldsGeneration(t *testing.T) {
+	const pkgPath = "./testdata/models"
+	golden := filepath.Join("testdata", "models", "fields.go")
+	out := filepath.Join(t.TempDir(), "fields.go")
+	if *update {
+		out = golden
+	}
+	if err := RunFieldsGeneration(pkgConfig, pkgPath, out); err != nil {
+		t.Fatalf("RunFieldsGeneration error: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Read generated file error: %v", err)
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Read %s error: %v", golden, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Generated fields differ from %s, run go test -update to accept them:\n%s", golden, got)
+	}
+
+	code := strings.Join(strings.Fields(string(got)), " ")
+	for _, snippet := range []string{
+		"Age goent.Col[int]",
+		"Tags goent.Col[[]string]",
+		"CreatedAt goent.Col[time.Time]",
+		"func UserCols(t *goent.Table[User]) *UserColumns {",
+		`Email: goent.NewCol[string](info, "email"),`,
+		`Nickname: goent.NewCol[string](info, "nick"),`,
+		`{Key: "nick", Value: t.Nickname},`,
+		"func RoleCols(t *goent.Table[Role]) *RoleColumns {",
+		"func (d *Database) C() DatabaseColumns {",
+		"User: UserCols(d.PublicSchema.User),",
+		"Role: RoleCols(d.PublicSchema.Role),",
+	} {
+		if !strings.Contains(code, snippet) {
+			t.Errorf("Generated fields do not contain %q", snippet)
+		}
+	}
+
+	pkgs, err := packages.Load(pkgConfig, pkgPath)
+	if err != nil || len(pkgs) != 1 {
+		t.Fatalf("Load fixture error: %v", err)
+	}
+	for _, e := range pkgs[0].Errors {
+		t.Errorf("Fixture does not compile with the generated fields: %v", e)
+	}
+}
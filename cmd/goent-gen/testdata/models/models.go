@@ -0,0 +1,43 @@
+// Package models is the fixture of the goent-gen fields test.
+package models
+
+import (
+	"time"
+
+	"github.com/azhai/goent"
+)
+
+// User is a model with a primary key tag.
+type User struct {
+	ID        int64  `goe:"pk"`
+	Name      string `goe:"index"`
+	Email     string `goe:"unique"`
+	Age       int
+	Nickname  string `goe:"column:nick"`
+	Tags      []string
+	CreatedAt time.Time
+}
+
+// Role is a model found through the schema.
+type Role struct {
+	ID   int64
+	Name string
+}
+
+// PublicSchema is the public schema of the database.
+type PublicSchema struct {
+	User *goent.Table[User]
+	Role *goent.Table[Role]
+}
+
+// Database is the database of the fixture.
+type Database struct {
+	PublicSchema `goe:"public"`
+	*goent.DB
+}
+
+// adults only compiles with the column references generated in fields.go.
+func adults(db *Database) *goent.StateSelect[User, User] {
+	c := db.C().User
+	return db.User.Select().Filter(c.Age.GreaterEquals(18)).OrderByCols(c.Name)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/azhai/goent"
+)
+
+func runMigrate(args *MigrateArgs) {
+	if args.Action == "create" {
+		if err := createMigration(args); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	env := NewEnvSafe()
+	dbType := resolveDBType(env)
+	cfg, err := ToDBConfig(args.DSN, dbType)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	db, err := OpenDB(cfg)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer CloseDB(db)
+
+	migs := db.Migrations()
+	if err = migs.LoadFiles(os.DirFS(args.Dir)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading migrations from %s: %v\n", args.Dir, err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	var count int
+	switch args.Action {
+	case "up":
+		count, err = migs.Up(ctx)
+		fmt.Printf("Applied %d migration(s)\n", count)
+	case "down":
+		count, err = migs.Down(ctx, args.Steps)
+		fmt.Printf("Reverted %d migration(s)\n", count)
+	case "redo":
+		err = migs.Redo(ctx)
+	case "status":
+		err = printMigrateStatus(ctx, migs)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown action %q, expected one of: up, down, status, redo, create\n", args.Action)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// createMigration writes an empty up and down migration with the next version, to fill by hand.
+// goent-opt has no models of the application, so it cannot diff them against the database,
+// call goent.GenerateMigration from the application for that.
+func createMigration(args *MigrateArgs) error {
+	if args.Name == "" {
+		return fmt.Errorf("--name is required for create")
+	}
+	path, err := goent.WriteMigrationFiles(args.Dir, args.Name, "", "")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created empty %s\n", path)
+	return nil
+}
+
+func printMigrateStatus(ctx context.Context, migs *goent.Migrations) error {
+	items, err := migs.Status(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-10s %-8s %-20s %s\n", "VERSION", "STATE", "APPLIED AT", "NAME")
+	for _, st := range items {
+		state, appliedAt := "pending", "-"
+		if st.Applied {
+			state = "applied"
+			appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if st.Missing {
+			state = "missing"
+		}
+		fmt.Printf("%-10d %-8s %-20s %s\n", st.Version, state, appliedAt, st.Name)
+	}
+	return nil
+}
@@ -137,8 +137,9 @@ func (w *TableWork) importSchemaSQL(sqlContent string) error {
 	return nil
 }
 
+// importData streams the JSON Lines of the data file into the table with CopyRows,
+// which is COPY on PostgreSQL and chunked inserts in one transaction on SQLite.
 func (w *TableWork) importData(cfg DBConfig, dataPath string) (int64, error) {
-	isPg := cfg.IsPg
 	f, err := os.Open(dataPath)
 	if err != nil {
 		return 0, fmt.Errorf("opening data file: %w", err)
@@ -150,79 +151,45 @@ func (w *TableWork) importData(cfg DBConfig, dataPath string) (int64, error) {
 		return 0, err
 	}
 
-	placeholders := make([]string, len(colTypes))
-	for i := range placeholders {
-		if len(colTypes) > 0 && isPg {
-			placeholders[i] = fmt.Sprintf("$%d", i+1)
-		} else {
-			placeholders[i] = "?"
-		}
-	}
-
-	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		quote(w.Table),
-		strings.Join(colTypes, ", "),
-		strings.Join(placeholders, ", "))
-
-	reader := bufio.NewReader(f)
-	var count int64
-	var batch []map[string]any
-	batchSize := 500
-
-	for {
-		line, err := reader.ReadString('\n')
-		line = strings.TrimSpace(line)
-		if line != "" {
-			var record map[string]any
-			if jsonErr := json.Unmarshal([]byte(line), &record); jsonErr != nil {
-				fmt.Printf("    Warning: skipping invalid JSON line: %v\n", jsonErr)
-				continue
-			}
-			batch = append(batch, record)
-		}
-
-		if len(batch) >= batchSize || (err != nil && len(batch) > 0) {
-			tx, txErr := w.BeginTx()
-			if txErr != nil {
-				return count, txErr
-			}
-			w.WithTx(tx)
-			if insertErr := w.insertBatch(insertSQL, colTypes, batch); insertErr != nil {
-				return count, insertErr
+	var readErr error
+	rows := func(yield func([]any) bool) {
+		reader := bufio.NewReader(f)
+		for {
+			line, err := reader.ReadString('\n')
+			line = strings.TrimSpace(line)
+			if line != "" {
+				var record map[string]any
+				if jsonErr := json.Unmarshal([]byte(line), &record); jsonErr != nil {
+					fmt.Printf("    Warning: skipping invalid JSON line: %v\n", jsonErr)
+				} else if !yield(recordValues(colTypes, record)) {
+					return
+				}
 			}
-			count += int64(len(batch))
-			batch = batch[:0]
-		}
-
-		if err != nil {
-			if err == io.EOF {
-				break
+			if err != nil {
+				if err != io.EOF {
+					readErr = err
+				}
+				return
 			}
-			return count, err
 		}
 	}
 
-	return count, nil
+	count, err := w.db.CopyRows(w.ctx, "", w.Table, colTypes, rows)
+	if err != nil {
+		return count, fmt.Errorf("copying rows: %w", err)
+	}
+	return count, readErr
 }
 
-func (w *TableWork) insertBatch(insertSQL string, colTypes []string, batch []map[string]any) error {
-	for _, record := range batch {
-		values := make([]any, len(colTypes))
-		for i, col := range colTypes {
-			val, ok := record[col]
-			if !ok {
-				values[i] = nil
-			} else {
-				values[i] = convertValue(val)
-			}
-		}
-		if err := w.TxExec(insertSQL, values...); err != nil {
-			w.tx.Rollback()
-			return fmt.Errorf("inserting row: %w", err)
+// recordValues returns the values of the record in the order of the columns.
+func recordValues(colTypes []string, record map[string]any) []any {
+	values := make([]any, len(colTypes))
+	for i, col := range colTypes {
+		if val, ok := record[col]; ok {
+			values[i] = convertValue(val)
 		}
 	}
-
-	return w.tx.Commit()
+	return values
 }
 
 func (w *TableWork) resetImportSequence() {
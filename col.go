@@ -0,0 +1,116 @@
+package goent
+
+// Col is a column reference which carries the Go type V of the column.
+// Its conditions only accept values of type V, so a mistyped value fails to compile
+// instead of failing at runtime. goent-gen fields generates the Col values of each model
+// and the C method of the Database type which returns them.
+//
+// Example:
+//
+//	c := db.C().Book
+//	books, err := db.Book.Select().Filter(c.Quantity.Greater(0)).OrderByCols(c.Title).All()
+type Col[V any] struct {
+	field *Field
+}
+
+// NewCol returns the typed reference of the column of a table.
+// It panics when the column does not exist, like TableInfo.Field.
+func NewCol[V any](info *TableInfo, name string) Col[V] {
+	return Col[V]{field: info.Field(name)}
+}
+
+// TableColumns returns the typed column references of a table, build creates them on the first call
+// and the later calls return the same value. goent-gen fields generates the build functions.
+func TableColumns[T, C any](t *Table[T], build func(info *TableInfo) C) C {
+	t.colsOnce.Do(func() { t.cols = build(t.TableInfo) })
+	cols, _ := t.cols.(C)
+	return cols
+}
+
+// Field returns the untyped field of the column, for the functions which take a *Field.
+func (c Col[V]) Field() *Field {
+	return c.field
+}
+
+// Desc returns a descending order of the column for OrderBy.
+func (c Col[V]) Desc() *Order {
+	return &Order{Field: c.field, Desc: true}
+}
+
+// Equals creates a condition that checks if the column is equal to a value.
+func (c Col[V]) Equals(value V) Condition {
+	return Equals(c.field, value)
+}
+
+// NotEquals creates a condition that checks if the column is not equal to a value.
+func (c Col[V]) NotEquals(value V) Condition {
+	return NotEquals(c.field, value)
+}
+
+// EqualsCol creates a condition that checks if the column is equal to another column of the same type.
+func (c Col[V]) EqualsCol(other Col[V]) Condition {
+	return EqualsField(c.field, other.field)
+}
+
+// Greater creates a condition that checks if the column is greater than a value.
+func (c Col[V]) Greater(value V) Condition {
+	return Greater(c.field, value)
+}
+
+// GreaterEquals creates a condition that checks if the column is greater than or equal to a value.
+func (c Col[V]) GreaterEquals(value V) Condition {
+	return GreaterEquals(c.field, value)
+}
+
+// Less creates a condition that checks if the column is less than a value.
+func (c Col[V]) Less(value V) Condition {
+	return Less(c.field, value)
+}
+
+// LessEquals creates a condition that checks if the column is less than or equal to a value.
+func (c Col[V]) LessEquals(value V) Condition {
+	return LessEquals(c.field, value)
+}
+
+// In creates a condition that checks if the column is one of the values.
+func (c Col[V]) In(values ...V) Condition {
+	return In(c.field, values)
+}
+
+// NotIn creates a condition that checks if the column is none of the values.
+func (c Col[V]) NotIn(values ...V) Condition {
+	return NotIn(c.field, values)
+}
+
+// IsNull creates a condition that checks if the column is NULL.
+func (c Col[V]) IsNull() Condition {
+	return IsNull(c.field)
+}
+
+// IsNotNull creates a condition that checks if the column is NOT NULL.
+func (c Col[V]) IsNotNull() Condition {
+	return IsNotNull(c.field)
+}
+
+// Like creates a LIKE condition on the column, for text columns.
+func (c Col[V]) Like(pattern string) Condition {
+	return Like(c.field, pattern)
+}
+
+// fieldRef is implemented by the typed column references.
+type fieldRef interface {
+	Field() *Field
+}
+
+// resolveField returns the field of a column given as a name, a *Field or a Col.
+func (info *TableInfo) resolveField(one any) (*Field, bool) {
+	switch f := one.(type) {
+	case string:
+		return info.Field(f), true
+	case *Field:
+		return f, true
+	case fieldRef:
+		return f.Field(), true
+	}
+	return nil, false
+}
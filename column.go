@@ -18,6 +18,7 @@ type Column struct {
 	tableName    string  // Table name (internal)
 	schemaName   *string // Schema name (internal)
 	isAutoIncr   bool    // Whether the column is auto-increment
+	isJSON       bool    // Whether the column is tagged with goe:"json"
 }
 
 // GetInt64 returns the int64 value of the column from the given object
@@ -0,0 +1,105 @@
+package goent
+
+import "github.com/azhai/goent/model"
+
+// compound is a SELECT combined with the query by a set operator.
+type compound struct {
+	operator string   // UNION, UNION ALL, INTERSECT or EXCEPT
+	member   *Builder // the combined SELECT
+}
+
+// buildCompounds writes the combined SELECTs after the query. Their ORDER BY, LIMIT and OFFSET
+// are left out, those of the query apply to the combined result.
+func (b *Builder) buildCompounds() []any {
+	c := &b.core
+	var args []any
+	for _, cp := range b.compounds {
+		c.buf.WriteByte(' ')
+		c.buf.WriteString(cp.operator)
+		c.buf.WriteByte(' ')
+		restore := cp.member.unpaged()
+		sql, memberArgs, lastIdx := cp.member.buildSubquery(c.argNo)
+		restore()
+		c.buf.WriteString(sql)
+		args = append(args, memberArgs...)
+		c.argNo = lastIdx
+	}
+	return args
+}
+
+// unpaged removes the ORDER BY, LIMIT and OFFSET of the builder, until restore is called.
+func (b *Builder) unpaged() (restore func()) {
+	orders, limit, offset := b.Orders, b.core.Limit, b.Offset
+	b.Orders, b.core.Limit, b.Offset = nil, TakeNoLimit, 0
+	return func() {
+		b.Orders, b.core.Limit, b.Offset = orders, limit, offset
+	}
+}
+
+// buildCount builds a query counting the rows of the compound SELECT.
+func (b *Builder) buildCount() (string, []any) {
+	restore := b.unpaged()
+	defer restore()
+	c := &b.core
+	c.buf.Reset()
+	c.argNo = 0
+	args := b.buildWith()
+	c.buf.WriteString("SELECT COUNT(*) FROM (")
+	args = append(args, b.buildNested()...)
+	c.buf.WriteString(") AS compound")
+	sql := c.buf.String()
+	c.buf.Reset()
+	return sql, args
+}
+
+// combine adds the SELECTs to the query with the set operator.
+func (s *StateSelect[T, R]) combine(operator string, others []Subquery) *StateSelect[T, R] {
+	for _, other := range others {
+		s.builder.compounds = append(s.builder.compounds, compound{operator, other.subqueryBuilder()})
+	}
+	return s
+}
+
+// Union combines the rows of the other SELECTs with the rows of the query, without duplicates.
+// The other SELECTs must have the same number and types of columns, they may select
+// other tables, their rows are scanned like the rows of the query.
+// OrderBy, Take, Skip and Pagination of the query apply to the combined rows, the order
+// by fields are the result columns. The other SELECTs must not be run on their own.
+//
+// Example:
+//
+//	names, err := db.Person.Select("name").
+//		UnionAll(db.Category.Select("name")).OrderBy("name").Take(20).All()
+func (s *StateSelect[T, R]) Union(others ...Subquery) *StateSelect[T, R] {
+	return s.combine("UNION", others)
+}
+
+// UnionAll combines the rows of the other SELECTs with the rows of the query, keeping duplicates.
+func (s *StateSelect[T, R]) UnionAll(others ...Subquery) *StateSelect[T, R] {
+	return s.combine("UNION ALL", others)
+}
+
+// Intersect keeps the rows of the query which the other SELECTs also return.
+func (s *StateSelect[T, R]) Intersect(others ...Subquery) *StateSelect[T, R] {
+	return s.combine("INTERSECT", others)
+}
+
+// Except keeps the rows of the query which the other SELECTs do not return.
+func (s *StateSelect[T, R]) Except(others ...Subquery) *StateSelect[T, R] {
+	return s.combine("EXCEPT", others)
+}
+
+// countCompound counts the rows of the compound SELECT, for Pagination.
+func (s *StateSelect[T, R]) countCompound() (int64, error) {
+	qr := model.CreateQuery(s.builder.buildCount())
+	conn, cfg := s.Prepare(s.table.TableInfo)
+	row, err := qr.WrapQueryRow(s.ctx, conn, cfg)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if qr.Err = row.Scan(&count); qr.Err != nil {
+		return 0, cfg.ErrorQueryHandler(s.ctx, qr)
+	}
+	return count, nil
+}
@@ -1,6 +1,7 @@
 package goent
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"slices"
@@ -15,6 +16,22 @@ type Condition struct {
 	Values   []*Value // Values to bind to the placeholders
 }
 
+// errorArg is the query argument of a condition which cannot be built,
+// the statement using it fails with the error when the driver reads its arguments.
+type errorArg struct {
+	err error
+}
+
+// Value implements the driver.Valuer interface, it returns the error.
+func (e errorArg) Value() (driver.Value, error) {
+	return nil, e.err
+}
+
+// errorCondition returns a condition which fails the statement using it with an error.
+func errorCondition(err error) Condition {
+	return Condition{Template: "?", Values: []*Value{NewValue(errorArg{err: err})}}
+}
+
 // IsEmpty returns true if the condition has no template (is empty)
 // It checks if the condition is effectively empty
 func (c Condition) IsEmpty() bool {
@@ -353,6 +370,41 @@ func NotIn(left *Field, value any) Condition {
 	return Condition{Template: "%s NOT IN ?", Fields: []*Field{left}, Values: []*Value{right}}
 }
 
+// InSelect creates a condition that checks if a field value is in the results of a subquery
+// It generates an IN (SELECT ...) clause, the subquery must select a single column
+// The subquery may compare its columns to the columns of the outer query
+//
+// Example:
+//
+//	authors := db.Post.Select("author_id").Filter(goent.Equals(db.Post.Field("published"), true))
+//	users, _ := db.User.Filter(goent.InSelect(db.User.Field("id"), authors)).Select().All()
+func InSelect(left *Field, sub Subquery) Condition {
+	return Condition{Template: "%s IN ?", Fields: []*Field{left}, Values: []*Value{NewValue(sub)}}
+}
+
+// NotInSelect creates a condition that checks if a field value is not in the results of a subquery
+// It generates a NOT IN (SELECT ...) clause, the subquery must select a single column
+func NotInSelect(left *Field, sub Subquery) Condition {
+	return Condition{Template: "%s NOT IN ?", Fields: []*Field{left}, Values: []*Value{NewValue(sub)}}
+}
+
+// Exists creates a condition that checks if a subquery returns any row
+// It generates an EXISTS (SELECT ...) clause, usually correlated to the outer query
+//
+// Example:
+//
+//	posts := db.Post.Select().Filter(goent.EqualsField(db.Post.Field("author_id"), db.User.Field("id")))
+//	authors, _ := db.User.Filter(goent.Exists(posts)).Select().All()
+func Exists(sub Subquery) Condition {
+	return Condition{Template: "EXISTS ?", Values: []*Value{NewValue(sub)}}
+}
+
+// NotExists creates a condition that checks if a subquery returns no row
+// It generates a NOT EXISTS (SELECT ...) clause
+func NotExists(sub Subquery) Condition {
+	return Condition{Template: "NOT EXISTS ?", Values: []*Value{NewValue(sub)}}
+}
+
 // Like creates a condition that checks if a field matches a LIKE pattern
 // It generates a LIKE clause for pattern matching
 func Like(left *Field, value string) Condition {
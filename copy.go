@@ -0,0 +1,121 @@
+package goent
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"strings"
+
+	"github.com/azhai/goent/model"
+)
+
+// Copy streams the rows into the table, for loads too big for one INSERT of All.
+// PostgreSQL uses the COPY protocol, SQLite runs chunked prepared INSERTs in one transaction.
+// Without OnTransaction the rows are copied in a transaction of their own.
+//
+// An auto-increment primary key is copied when the first row has one, otherwise the database
+// generates it and the rows are not updated. The auto_create_time and auto_update_time columns
+// are filled, but Copy runs no hooks and publishes no events.
+// It returns the number of copied rows.
+//
+// Example:
+//
+//	n, err := db.Event.Insert().OnTransaction(tx).Copy(slices.Values(events))
+func (s *StateInsert[T]) Copy(rows iter.Seq[*T]) (int64, error) {
+	defer PutBuilder(s.builder)
+	next, stop := iter.Pull(rows)
+	defer stop()
+	first, ok := next()
+	for ok && first == nil {
+		first, ok = next()
+	}
+	if !ok {
+		return 0, nil
+	}
+
+	info := s.table.TableInfo
+	pkFid, pkName, _ := info.GetPrimaryInfo()
+	skipPK := pkFid >= 0 && len(info.PrimaryKeys) > 0 && info.PrimaryKeys[0].IsAutoIncr &&
+		reflect.ValueOf(first).Elem().Field(pkFid).IsZero()
+	columns := make([]string, 0, len(info.ColumnNames))
+	fieldIds := make([]int, 0, len(info.ColumnNames))
+	jsonIds := make([]bool, 0, len(info.ColumnNames))
+	for _, name := range info.ColumnNames {
+		col := info.Columns[name]
+		if skipPK && col.ColumnName == pkName {
+			continue
+		}
+		columns = append(columns, col.ColumnName)
+		fieldIds = append(fieldIds, col.FieldId)
+		jsonIds = append(jsonIds, col.isJSON)
+	}
+
+	values := func(yield func([]any) bool) {
+		for obj, ok := first, true; ok; obj, ok = next() {
+			if obj == nil {
+				continue
+			}
+			valueOf := reflect.ValueOf(obj).Elem()
+			info.fillAutoTimes(valueOf, false)
+			row := make([]any, len(fieldIds))
+			for i, fid := range fieldIds {
+				if row[i] = valueOf.Field(fid).Interface(); jsonIds[i] {
+					row[i] = jsonArg(row[i])
+				}
+			}
+			if !yield(row) {
+				return
+			}
+		}
+	}
+	conn, cfg := s.Prepare(info)
+	return copyRows(s.ctx, conn, cfg, info.SchemaName, info.TableName, columns, values)
+}
+
+// CopyFrom streams the rows into the table with Insert().Copy(), see there for the details.
+//
+// Example:
+//
+//	n, err := db.Event.CopyFrom(func(yield func(*Event) bool) {
+//		for scanner.Scan() {
+//			if !yield(parseEvent(scanner.Text())) {
+//				return
+//			}
+//		}
+//	})
+func (t *Table[T]) CopyFrom(rows iter.Seq[*T]) (int64, error) {
+	return t.CopyFromContext(context.Background(), rows)
+}
+
+// CopyFromContext streams the rows into the table with a specific context.
+func (t *Table[T]) CopyFromContext(ctx context.Context, rows iter.Seq[*T]) (int64, error) {
+	return t.InsertContext(ctx).Copy(rows)
+}
+
+// CopyRows streams rows of values into the columns of a table which has no model,
+// like Table.CopyFrom does. The schema is ignored by SQLite.
+//
+// Example:
+//
+//	n, err := db.CopyRows(ctx, "public", "event", []string{"name", "at"}, rows)
+func (db *DB) CopyRows(ctx context.Context, schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	if db == nil || db.driver == nil {
+		return 0, model.ErrDBNotFound
+	}
+	return copyRows(ctx, db.driver.NewConnection(), db.driver.GetDatabaseConfig(), schema, table, columns, rows)
+}
+
+// copyRows streams the rows through the Copier of the connection and logs a failed copy like a query.
+func copyRows(ctx context.Context, conn model.Connection, cfg *model.DatabaseConfig,
+	schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	copier, ok := conn.(model.Copier)
+	if !ok {
+		return 0, model.ErrCopyNotSupported
+	}
+	n, err := copier.CopyFrom(ctx, schema, table, columns, rows)
+	if err != nil {
+		qr := model.Query{RawSql: "COPY " + table + " (" + strings.Join(columns, ", ") + ")", Err: err}
+		return n, cfg.ErrorQueryHandler(ctx, qr)
+	}
+	return n, nil
+}
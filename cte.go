@@ -0,0 +1,193 @@
+package goent
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/azhai/goent/model"
+)
+
+// CTE is a named common table expression, declared in the WITH clause of a SELECT.
+// Queries select from it with FromCTE, join it with JoinCTE and reference its columns with Field.
+// The StateSelects of a CTE are only built, they must not be run on their own.
+type CTE struct {
+	Name    string   // Name of the CTE, used like a table name
+	Columns []string // Optional column names of the CTE
+
+	recursive bool       // WITH RECURSIVE, the CTE references itself
+	union     string     // UNION or UNION ALL between the members
+	members   []*Builder // SELECTs of the CTE, combined with union
+	raw       Condition  // raw SQL of the CTE, instead of the members
+}
+
+// NewCTE creates a CTE from a SELECT.
+//
+// Example:
+//
+//	active := goent.NewCTE("active_user", db.User.Select().Filter(goent.Equals(db.User.Field("active"), true)))
+//	users, err := db.User.Select().FromCTE(active).All()
+func NewCTE(name string, query Subquery, columns ...string) *CTE {
+	return &CTE{Name: name, Columns: columns, members: []*Builder{query.subqueryBuilder()}}
+}
+
+// NewRawCTE creates a CTE from raw SQL, which may have ? placeholders for the args.
+// Call Recursive when the SQL references the CTE itself.
+//
+// Example:
+//
+//	days := goent.NewRawCTE("day", "SELECT 1 AS n UNION ALL SELECT n + 1 FROM day WHERE n < ?", 7).Recursive()
+func NewRawCTE(name, sql string, args ...any) *CTE {
+	return &CTE{Name: name, raw: Expr(sql, args...)}
+}
+
+// NewRecursiveCTE creates a WITH RECURSIVE CTE, its members are set by UnionAll or Union.
+// The CTE is created first, so the recursive member can join it.
+//
+// Example:
+//
+//	tree := goent.NewRecursiveCTE("subtree")
+//	tree.UnionAll(
+//		db.Category.Select().Filter(goent.Equals(db.Category.Field("id"), rootId)),
+//		db.Category.Select().JoinCTE(model.InnerJoin, tree,
+//			goent.EqualsField(db.Category.Field("parent_id"), tree.Field("id"))),
+//	)
+//	categories, err := db.Category.Select().FromCTE(tree).All()
+func NewRecursiveCTE(name string, columns ...string) *CTE {
+	return &CTE{Name: name, Columns: columns, recursive: true}
+}
+
+// UnionAll sets the members of the CTE, combined with UNION ALL.
+// For a recursive CTE they are the anchor member followed by the recursive member.
+func (c *CTE) UnionAll(members ...Subquery) *CTE {
+	return c.setMembers("UNION ALL", members)
+}
+
+// Union sets the members of the CTE, combined with UNION, which also stops the
+// recursion on rows already found, e.g. on a graph with cycles.
+func (c *CTE) Union(members ...Subquery) *CTE {
+	return c.setMembers("UNION", members)
+}
+
+// setMembers sets the members of the CTE and the operator between them.
+func (c *CTE) setMembers(union string, members []Subquery) *CTE {
+	c.union, c.members, c.raw = union, make([]*Builder, len(members)), Condition{}
+	for i, member := range members {
+		c.members[i] = member.subqueryBuilder()
+	}
+	return c
+}
+
+// Recursive marks the CTE as recursive, for a raw CTE which references itself.
+func (c *CTE) Recursive() *CTE {
+	c.recursive = true
+	return c
+}
+
+// Field returns a field referencing a column of the CTE, for conditions and joins.
+func (c *CTE) Field(column string) *Field {
+	return &Field{ColumnName: c.Name + "." + column, FieldId: -1}
+}
+
+// declareCTEs appends the CTEs to the list after the CTEs their members use,
+// so each CTE is declared after the ones it depends on.
+// A recursive CTE is already seen when its own member joins it.
+func declareCTEs(list, ctes []*CTE, seen map[*CTE]bool) []*CTE {
+	for _, cte := range ctes {
+		if seen[cte] {
+			continue
+		}
+		seen[cte] = true
+		for _, member := range cte.members {
+			list = declareCTEs(list, member.ctes, seen)
+		}
+		list = append(list, cte)
+	}
+	return list
+}
+
+// write writes the CTE declaration: name (columns) AS (members).
+func (c *CTE) write(core *BuilderCore, args *[]any) {
+	core.buf.WriteString(c.Name)
+	if len(c.Columns) > 0 {
+		core.buf.WriteString(" (")
+		core.buf.WriteString(strings.Join(c.Columns, ", "))
+		core.buf.WriteByte(')')
+	}
+	core.buf.WriteString(" AS (")
+	if c.raw.IsEmpty() {
+		for i, member := range c.members {
+			if i > 0 {
+				core.buf.WriteByte(' ')
+				core.buf.WriteString(c.union)
+				core.buf.WriteByte(' ')
+			}
+			sql, memberArgs, lastIdx := member.buildSubquery(core.argNo)
+			core.buf.WriteString(sql)
+			*args = append(*args, memberArgs...)
+			core.argNo = lastIdx
+		}
+	} else {
+		core.argNo = core.buildTemplate(c.raw, args, core.argNo, true)
+	}
+	core.buf.WriteByte(')')
+}
+
+// addCTE adds the CTE to the WITH clause of the builder, once.
+func (b *Builder) addCTE(cte *CTE) {
+	if !slices.Contains(b.ctes, cte) {
+		b.ctes = append(b.ctes, cte)
+	}
+}
+
+// buildWith builds the WITH clause of the CTEs of the query and the CTEs they depend on.
+// Subqueries and the members of CTEs have no WITH clause, they use the CTEs of the top query.
+func (b *Builder) buildWith() []any {
+	if len(b.ctes) == 0 {
+		return nil
+	}
+	list := declareCTEs(nil, b.ctes, make(map[*CTE]bool))
+	c := &b.core
+	c.buf.WriteString("WITH ")
+	if slices.ContainsFunc(list, func(cte *CTE) bool { return cte.recursive }) {
+		c.buf.WriteString("RECURSIVE ")
+	}
+	var args []any
+	for i, cte := range list {
+		if i > 0 {
+			c.buf.WriteString(", ")
+		}
+		cte.write(c, &args)
+	}
+	c.buf.WriteByte(' ')
+	return args
+}
+
+// WithCTE declares the CTEs in the WITH clause of the query, to be used by its conditions.
+// FromCTE and JoinCTE declare their CTE themselves.
+func (s *StateSelect[T, R]) WithCTE(ctes ...*CTE) *StateSelect[T, R] {
+	for _, cte := range ctes {
+		s.builder.addCTE(cte)
+	}
+	return s
+}
+
+// FromCTE selects the rows of the CTE instead of the table. The CTE is aliased with the
+// table name, so it must have the columns of the table which the query uses.
+func (s *StateSelect[T, R]) FromCTE(cte *CTE) *StateSelect[T, R] {
+	s.builder.addCTE(cte)
+	s.builder.fromCTE = cte
+	return s
+}
+
+// JoinCTE joins the CTE to the query, the on condition references its columns with cte.Field.
+func (s *StateSelect[T, R]) JoinCTE(joinType model.JoinType, cte *CTE, on Condition) *StateSelect[T, R] {
+	s.builder.addCTE(cte)
+	s.builder.Type = model.SelectJoinQuery
+	s.builder.Joins = append(s.builder.Joins, &JoinTable{
+		JoinType: joinType,
+		Table:    &model.Table{Name: cte.Name},
+		fullName: cte.Name,
+		On:       on,
+	})
+	return s
+}
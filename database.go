@@ -67,8 +67,10 @@ func GetTableFieldName(addr uintptr, name string) (string, error) {
 // DB represents a database connection with its driver
 // It provides methods for executing queries and managing transactions
 type DB struct {
-	bus    *gobus.EventBus
-	driver model.Driver // Database driver implementation
+	bus         *gobus.EventBus
+	driver      model.Driver // Database driver implementation
+	migrations  *Migrations  // Versioned migrations, see [DB.Migrations]
+	migrateOnce sync.Once
 }
 
 // SetDriver sets the database driver
@@ -303,7 +305,8 @@ func (db *DB) Upsert(table string, columns, conflictCols []string, values ...any
 }
 
 // Close closes the database connection and cleans up the table registry
-// It closes the underlying driver connection and resets the registry
+// It closes the underlying driver connection and unregisters the tables of the database,
+// the tables of the other open databases stay registered
 func Close(ent any) error {
 	goeDb := getDatabase(ent)
 	err := goeDb.driver.Close()
@@ -312,10 +315,25 @@ func Close(ent any) error {
 		return dc.ErrorHandler(context.TODO(), err)
 	}
 
-	ResetRegistry()
+	unregisterTables(goeDb)
 	return nil
 }
 
+// unregisterTables removes the tables of a database from the registry,
+// and the schemas too when no table is left.
+func unregisterTables(db *DB) {
+	tableRegLock.Lock()
+	defer tableRegLock.Unlock()
+	for addr, info := range tableRegistry {
+		if info.db == db {
+			delete(tableRegistry, addr)
+		}
+	}
+	if len(tableRegistry) == 0 {
+		schemaRegistry = make(map[string]*string)
+	}
+}
+
 func getDatabase(ent any) *DB {
 	valueOf := reflect.ValueOf(ent).Elem()
 	return valueOf.Field(valueOf.NumField() - 1).Interface().(*DB)
@@ -17,6 +17,7 @@ type NilMarker struct{}
 type StateDelete[T any] struct {
 	table             *Table[T] // The table to delete records from
 	skipEvent         bool      // Internal: skip event publishing (used by StateDeleteByID)
+	unscoped          bool      // Remove the rows even if the table has a soft delete column
 	*StateDeleteWhere           // Embedded StateDeleteWhere for WHERE clause construction
 }
 
@@ -27,21 +28,62 @@ func (s *StateDelete[T]) Match(obj T) *StateDelete[T] {
 	return s
 }
 
+// Unscoped makes the delete remove the rows from the table,
+// even if the table has a goe:"soft_delete" column.
+func (s *StateDelete[T]) Unscoped() *StateDelete[T] {
+	s.unscoped = true
+	return s
+}
+
+// ForceDelete removes the matching rows from the table, bypassing soft delete.
+//
+// Example:
+//
+//	err := db.User.Filter(goent.Equals(db.User.Field("id"), 1)).Delete().ForceDelete()
+func (s *StateDelete[T]) ForceDelete() error {
+	return s.Unscoped().Exec()
+}
+
+// isSoftDelete returns true if the delete marks the rows as deleted instead of removing them.
+func (s *StateDelete[T]) isSoftDelete() bool {
+	return !s.unscoped && s.table.HasSoftDelete()
+}
+
 // Exec executes the DELETE query
-// It builds and runs the DELETE statement with the specified conditions
+// It builds and runs the DELETE statement with the specified conditions,
+// for a table with a soft delete column it runs an UPDATE setting the column instead.
 func (s *StateDelete[T]) Exec() error {
+	stmt := hookStatement[T](s.table.TableInfo, s.builder.core.Where, nil, hookBeforeDelete, hookAfterDelete)
+	if err := runStatementHook[T](s.ctx, s.conn, hookBeforeDelete, stmt); err != nil {
+		PutDeleteBuilder(s.builder)
+		return err
+	}
+	affected, err := s.exec()
+	if err != nil || stmt == nil {
+		return err
+	}
+	stmt.Affected = affected
+	return runStatementHook[T](s.ctx, s.conn, hookAfterDelete, stmt)
+}
+
+// exec builds and runs the DELETE statement, or the UPDATE of a soft delete, then publishes its event.
+// It returns the number of affected rows.
+func (s *StateDelete[T]) exec() (int64, error) {
+	defer PutDeleteBuilder(s.builder)
+	if s.isSoftDelete() {
+		return softDeleteRows(s.ctx, s.conn, s.table, s.builder.core.Where,
+			EventTopicDelete, nil, s.skipEvent)
+	}
 	s.builder.SetTable(s.table.TableInfo)
 	sql, args := s.builder.Build()
 	if sql == "" {
-		defer PutDeleteBuilder(s.builder)
-		return fmt.Errorf("goent: StateDelete.Exec built empty SQL (fullName=%q, Where=%v, args=%v)",
+		return 0, fmt.Errorf("goent: StateDelete.Exec built empty SQL (fullName=%q, Where=%v, args=%v)",
 			s.builder.core.fullName, !s.builder.core.Where.IsEmpty(), args)
 	}
 	qr := model.CreateQuery(sql, args)
-	defer PutDeleteBuilder(s.builder)
 	conn, cfg := s.Prepare(s.table.TableInfo)
 	if err := qr.WrapExec(s.ctx, conn, cfg); err != nil {
-		return err
+		return 0, err
 	}
 	// Skip event for clear-all operations (no WHERE) and internal byid calls
 	if !s.skipEvent && !s.builder.core.Where.IsEmpty() {
@@ -49,25 +91,77 @@ func (s *StateDelete[T]) Exec() error {
 		publishEvent(info.db.bus, info, conn, EventTopicDelete,
 			s.builder.core.Where.Template, nil, nil, qr.RowsAffected)
 	}
-	return nil
+	return qr.RowsAffected, nil
+}
+
+// execRow executes the DELETE of a single row matching the key condition.
+// When the model has the row hooks, the row is loaded before the delete for BeforeDelete and AfterDelete.
+func (s *StateDelete[T]) execRow(key Condition, exec func() (int64, error)) error {
+	var row *T
+	hctx := hookContext(s.ctx, s.conn)
+	if hasHook[T](hookBeforeDelete) || hasHook[T](hookAfterDelete) {
+		var err error
+		row, err = s.table.hookRow(s.ctx, s.conn, key, !s.isSoftDelete())
+		if err == nil && row != nil {
+			err = runHook(hctx, hookBeforeDelete, row)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	affected, err := exec()
+	if err != nil || row == nil || affected == 0 {
+		return err
+	}
+	return runHook(hctx, hookAfterDelete, row)
 }
 
 // ByPK deletes a single row by primary key using cached SQL.
 // This is an optimized path that bypasses query building for simple primary key deletions.
-// Only works for tables with a single primary key column.
-func (s *StateDelete[T]) ByPK(id int64) error {
-	sql := s.table.GetDeleteByPKSql()
-	if sql == "" {
+// Only works for tables with a single primary key column, the key can be of any type.
+func (s *StateDelete[T]) ByPK(id any) error {
+	pkField := s.table.GetPKField()
+	if pkField == nil {
 		return model.ErrNoPrimaryKey
 	}
-	conn, cfg := s.Prepare(s.table.TableInfo)
-	qr := model.CreateQuery(sql, []any{id})
-	if err := qr.WrapExec(s.ctx, conn, cfg); err != nil {
+	key := Equals(pkField, id)
+	return s.execRow(key, func() (int64, error) {
+		if s.isSoftDelete() {
+			return softDeleteRows(s.ctx, s.conn, s.table, key, EventTopicDeleteByPK, []any{id}, false)
+		}
+		sql := s.table.GetDeleteByPKSql()
+		if sql == "" {
+			return 0, model.ErrNoPrimaryKey
+		}
+		conn, cfg := s.Prepare(s.table.TableInfo)
+		qr := model.CreateQuery(sql, []any{id})
+		if err := qr.WrapExec(s.ctx, conn, cfg); err != nil {
+			return 0, err
+		}
+		info := s.table.TableInfo
+		publishKeysEvent(info.db.bus, info, conn, EventTopicDeleteByPK, "", []any{id}, nil, 1)
+		return qr.RowsAffected, nil
+	})
+}
+
+// ByKeys deletes a single row by its full primary key, which may span several columns.
+// The keys are either one value per primary key column in struct field order,
+// or a single Dict keyed by column name.
+func (s *StateDelete[T]) ByKeys(keys ...any) error {
+	cond, data, err := s.table.keysCondition(keys)
+	if err != nil {
 		return err
 	}
-	info := s.table.TableInfo
-	publishEvent(info.db.bus, info, conn, EventTopicDeleteByPK, "", []int64{id}, nil, 1)
-	return nil
+	s.builder.core.Where = cond
+	s.skipEvent = true
+	return s.execRow(cond, func() (int64, error) {
+		affected, err := s.exec()
+		if err == nil {
+			info := s.table.TableInfo
+			publishKeysEvent(info.db.bus, info, s.conn, EventTopicDeleteByPK, "", []any{data}, nil, 1)
+		}
+		return affected, err
+	})
 }
 
 // OnTransaction sets the transaction for the DELETE operation
@@ -233,7 +327,8 @@ func MatchFilter[T any](table *Table[T], obj T) Condition {
 //   - Provides the list of deleted IDs for auditing
 //   - Uses InBatch to handle large ID sets within parameter limits
 //
-// Only works for tables with a single integer primary key.
+// Only works for tables with a single primary key. Exec returns int64 IDs,
+// use ExecKeys for string or UUID primary keys.
 //
 // Example:
 //
@@ -269,14 +364,21 @@ func (s *StateDeleteByID[T]) OnTransaction(tx model.Transaction) *StateDeleteByI
 // Exec executes the two-phase delete.
 // Phase 1: SELECT pk FROM table WHERE <conditions> [LIMIT n]
 // Phase 2: DELETE FROM table WHERE pk IN (ids)  (batched via InBatch)
-// Returns the list of deleted IDs.
+// Returns the list of deleted IDs, or nil IDs when the primary key is not an integer.
 func (s *StateDeleteByID[T]) Exec() ([]int64, error) {
-	ids, cond, err := s.buildInBatchCond()
+	keys, err := s.ExecKeys()
+	return int64Keys(keys), err
+}
+
+// ExecKeys executes the two-phase delete like Exec.
+// It returns the deleted primary key values in their Go type, e.g. strings or UUIDs.
+func (s *StateDeleteByID[T]) ExecKeys() ([]any, error) {
+	keys, cond, err := s.buildInBatchCond()
 	if err != nil {
 		return nil, err
 	}
-	if len(ids) == 0 {
-		return ids, nil
+	if len(keys) == 0 {
+		return keys, nil
 	}
 
 	sd := NewStateDeleteWhere(s.ctx)
@@ -285,11 +387,11 @@ func (s *StateDeleteByID[T]) Exec() ([]int64, error) {
 
 	del := &StateDelete[T]{table: s.table, StateDeleteWhere: sd, skipEvent: true}
 	if err := del.Exec(); err != nil {
-		return ids, err
+		return keys, err
 	}
-	// Send byid event with the queried IDs
+	// Send byid event with the queried keys
 	info := s.table.TableInfo
-	publishEvent(info.db.bus, info, s.conn, EventTopicDeleteByID,
-		s.where.Template, ids, nil, int64(len(ids)))
-	return ids, nil
+	publishKeysEvent(info.db.bus, info, s.conn, EventTopicDeleteByID,
+		s.where.Template, keys, nil, int64(len(keys)))
+	return keys, nil
 }
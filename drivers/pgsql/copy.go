@@ -0,0 +1,41 @@
+package pgsql
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CopyFrom streams the rows into the table with the COPY protocol.
+func (c Connection) CopyFrom(ctx context.Context, schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	next, stop := iter.Pull(rows)
+	defer stop()
+	return c.sql.CopyFrom(ctx, copyTable(schema, table), columns, copySource(next))
+}
+
+// CopyFrom streams the rows into the table with the COPY protocol, inside the transaction.
+func (t Transaction) CopyFrom(ctx context.Context, schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	next, stop := iter.Pull(rows)
+	defer stop()
+	return t.tx.CopyFrom(ctx, copyTable(schema, table), columns, copySource(next))
+}
+
+// copyTable returns the identifier of the table, pgx quotes each part.
+func copyTable(schema, table string) pgx.Identifier {
+	if schema != "" {
+		return pgx.Identifier{schema, table}
+	}
+	return pgx.Identifier{table}
+}
+
+// copySource adapts a pulled iterator to the source of a COPY, a nil row ends the data.
+func copySource(next func() ([]any, bool)) pgx.CopyFromSource {
+	return pgx.CopyFromFunc(func() ([]any, error) {
+		row, ok := next()
+		if !ok {
+			return nil, nil
+		}
+		return row, nil
+	})
+}
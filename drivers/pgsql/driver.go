@@ -13,6 +13,7 @@ import (
 	"github.com/azhai/goent/model"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,6 +26,19 @@ func (rs *Rows) Close() error {
 	return rs.Err()
 }
 
+func (rs *Rows) Columns() ([]string, error) {
+	fields := rs.FieldDescriptions()
+	names := make([]string, len(fields))
+	for i, fd := range fields {
+		names[i] = fd.Name
+	}
+	return names, nil
+}
+
+func (rs *Rows) Scan(dest ...any) error {
+	return rs.Rows.Scan(nativeDest(dest)...)
+}
+
 type Row struct {
 	pgx.Row
 	err error
@@ -34,13 +48,39 @@ func (r Row) Scan(dest ...any) error {
 	if r.err != nil {
 		return r.err
 	}
-	err := r.Row.Scan(dest...)
+	err := r.Row.Scan(nativeDest(dest)...)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return sql.ErrNoRows
 	}
 	return err
 }
 
+// nativeDest replaces the destinations which pgx decodes natively, like arrays,
+// by the model fields they wrap.
+func nativeDest(dest []any) []any {
+	for i, d := range dest {
+		if nd, ok := d.(model.NativeDest); ok {
+			dest[i] = nd.NativeDest()
+		}
+	}
+	return dest
+}
+
+// rangeFormats asks for the range columns in text format, which goent.Range scans.
+var rangeFormats = pgx.QueryResultFormatsByOID{
+	pgtype.Int4rangeOID: pgx.TextFormatCode,
+	pgtype.Int8rangeOID: pgx.TextFormatCode,
+	pgtype.NumrangeOID:  pgx.TextFormatCode,
+	pgtype.TsrangeOID:   pgx.TextFormatCode,
+	pgtype.TstzrangeOID: pgx.TextFormatCode,
+	pgtype.DaterangeOID: pgx.TextFormatCode,
+}
+
+// queryArgs prepends the result formats to the arguments of a query.
+func queryArgs(args []any) []any {
+	return append([]any{rangeFormats}, args...)
+}
+
 // Driver implements the PostgreSQL database driver using pgx.
 type Driver struct {
 	dsn string
@@ -253,7 +293,7 @@ func (c Connection) QueryContext(ctx context.Context, query *model.Query) (model
 	if query.RawSql == "" {
 		return nil, fmt.Errorf("goent: attempted to query empty SQL (Arguments=%v)", query.Arguments)
 	}
-	rows, err := c.sql.Query(ctx, query.RawSql, query.Arguments...)
+	rows, err := c.sql.Query(ctx, query.RawSql, queryArgs(query.Arguments)...)
 	return &Rows{rows}, err
 }
 
@@ -261,7 +301,7 @@ func (c Connection) QueryRowContext(ctx context.Context, query *model.Query) mod
 	if query.RawSql == "" {
 		return Row{err: fmt.Errorf("goent: attempted to query row with empty SQL (Arguments=%v)", query.Arguments)}
 	}
-	return Row{Row: c.sql.QueryRow(ctx, query.RawSql, query.Arguments...)}
+	return Row{Row: c.sql.QueryRow(ctx, query.RawSql, queryArgs(query.Arguments)...)}
 }
 
 func (c Connection) ExecContext(ctx context.Context, query *model.Query) error {
@@ -287,7 +327,7 @@ type Transaction struct {
 }
 
 func (t Transaction) QueryContext(ctx context.Context, query *model.Query) (model.Rows, error) {
-	rows, err := t.tx.Query(ctx, query.RawSql, query.Arguments...)
+	rows, err := t.tx.Query(ctx, query.RawSql, queryArgs(query.Arguments)...)
 	return &Rows{rows}, err
 }
 
@@ -295,7 +335,7 @@ func (t Transaction) QueryRowContext(ctx context.Context, query *model.Query) mo
 	if query.RawSql == "" {
 		return Row{err: fmt.Errorf("goent: attempted to query row with empty SQL (Arguments=%v)", query.Arguments)}
 	}
-	return Row{Row: t.tx.QueryRow(ctx, query.RawSql, query.Arguments...)}
+	return Row{Row: t.tx.QueryRow(ctx, query.RawSql, queryArgs(query.Arguments)...)}
 }
 
 func (t Transaction) ExecContext(ctx context.Context, query *model.Query) error {
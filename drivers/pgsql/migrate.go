@@ -25,6 +25,18 @@ func (dr *Driver) MigrateContext(ctx context.Context, migrator *model.Migrator)
 		"time.Time": {"timestamp", "to_timestamp(0)"},
 		"bool":      {"boolean", "false"},
 		"uuid.UUID": {"uuid", "'00000000-0000-0000-0000-000000000000'"},
+		"json":      {"jsonb", "'null'"},
+		"[]int16":   {"int2[]", "'{}'"},
+		"[]int32":   {"int4[]", "'{}'"},
+		"[]int64":   {"int8[]", "'{}'"},
+		"[]float32": {"float4[]", "'{}'"},
+		"[]float64": {"float8[]", "'{}'"},
+		"[]string":  {"text[]", "'{}'"},
+		"[]bool":    {"bool[]", "'{}'"},
+		"int4range": {"int4range", "'empty'"},
+		"int8range": {"int8range", "'empty'"},
+		"numrange":  {"numrange", "'empty'"},
+		"tsrange":   {"tsrange", "'empty'"},
 	}
 
 	sql := new(strings.Builder)
@@ -42,31 +54,38 @@ func (dr *Driver) MigrateContext(ctx context.Context, migrator *model.Migrator)
 	}
 
 	for _, t := range migrator.Tables {
-		err = checkTableChanges(t, dataMap, sql, dr.sql)
+		err = checkTableChanges(t, dataMap, sql, dr.sql, migrator.Plan)
 		if err != nil {
 			return err
 		}
 
-		err = checkIndex(t.Indexes, t, sqlColumns, dr.sql)
+		err = checkIndex(t.Indexes, t, sqlColumns, dr.sql, migrator.Plan)
 		if err != nil {
 			return err
 		}
+		if t.Migrated {
+			checkForeignKeys(t, sqlForeignKeys, dr.sql, migrator.Plan)
+		}
 	}
 
 	for _, t := range migrator.Tables {
 		if !t.Migrated {
-			createTable(t, dataMap, sql, migrator.Tables, sqlForeignKeys)
+			createTable(t, dataMap, sql, migrator.Tables, sqlForeignKeys, migrator.Plan)
 		}
 	}
 
 	sql.WriteString(sqlColumns.String())
 	sql.WriteString(sqlForeignKeys.String())
 
-	if sql.Len() != 0 {
-		schemas.WriteString(sql.String())
-		return dr.rawExecContext(ctx, schemas.String())
+	if sql.Len() == 0 {
+		return nil
 	}
-	return nil
+	schemas.WriteString(sql.String())
+	if migrator.Plan != nil {
+		migrator.Plan.Sql = schemas.String()
+		return nil
+	}
+	return dr.rawExecContext(ctx, schemas.String())
 }
 
 func (dr *Driver) rawExecContext(ctx context.Context, rawSql string, args ...any) error {
@@ -156,7 +175,7 @@ func (dr *Driver) DropColumn(schema, table, column string) error {
 	return dr.rawExecContext(context.TODO(), dropColumn(table, column))
 }
 
-func checkTableChanges(table *model.TableMigrate, dataMap map[string]dataType, sql *strings.Builder, conn *pgxpool.Pool) error {
+func checkTableChanges(table *model.TableMigrate, dataMap map[string]dataType, sql *strings.Builder, conn *pgxpool.Pool, plan *model.MigrationPlan) error {
 	schema := "public"
 	if table.Schema != nil && *table.Schema != "" {
 		schema = *table.Schema
@@ -169,11 +188,25 @@ func checkTableChanges(table *model.TableMigrate, dataMap map[string]dataType, s
 		return nil
 	}
 	table.Migrated = true
-	checkFields(conn, dbTbl, table, dataMap, sql)
+	checkFields(conn, dbTbl, table, dataMap, sql, plan)
 
 	return nil
 }
 
+// checkForeignKeys adds the foreign keys spanning several columns which are missing on an existing table
+func checkForeignKeys(table *model.TableMigrate, sql *strings.Builder, conn *pgxpool.Pool, plan *model.MigrationPlan) {
+	schema := "public"
+	if table.Schema != nil && *table.Schema != "" {
+		schema = *table.Schema
+	}
+	for _, fk := range table.ForeignKeys {
+		if !checkFkComposite(conn, schema, table.Name, fk.Name) {
+			sql.WriteString(addFkComposite(table, fk))
+			plan.AddForeignKey(table, strings.Join(fk.Columns, ","), fk.EscapingTargetTableName()+"("+strings.Join(fk.EscapingTargetColumns, ",")+")")
+		}
+	}
+}
+
 func primaryKeyIsForeignKey(table *model.TableMigrate, attName string) bool {
 	return slices.ContainsFunc(table.ManyToSomes, func(m model.ManyToSomeMigrate) bool {
 		return m.Name == attName
@@ -189,7 +222,7 @@ func foreignKeyIsPrimarykey(table *model.TableMigrate, attName string) bool {
 	return slices.ContainsFunc(table.PrimaryKeys, isSameName)
 }
 
-func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *strings.Builder, tables map[string]*model.TableMigrate, sqlForeignKeys *strings.Builder) {
+func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *strings.Builder, tables map[string]*model.TableMigrate, sqlForeignKeys *strings.Builder, plan *model.MigrationPlan) {
 	t := table{}
 	t.name = fmt.Sprintf("CREATE TABLE %v (", tbl.EscapingTableName())
 	processedAttrs := make(map[string]bool)
@@ -214,6 +247,7 @@ func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *stri
 				if targetTable, targetCol := findForeignKey(tbl, att.Name); targetTable != "" {
 					sqlForeignKeys.WriteString(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT fk_%v_%v FOREIGN KEY (%v) REFERENCES %v(%v);\n",
 						tbl.EscapingTableName(), tbl.Name, att.Name, att.EscapingName, targetTable, targetCol))
+					plan.AddForeignKey(tbl, att.Name, targetTable+"("+targetCol+")")
 				}
 			}
 		} else if col.Attr != nil {
@@ -227,6 +261,7 @@ func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *stri
 				t.createAttrs = append(t.createAttrs, fmt.Sprintf("%v %v %v,", att.EscapingName, att.DataType, feature))
 				sqlForeignKeys.WriteString(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT fk_%v_%v FOREIGN KEY (%v) REFERENCES %v(%v);\n",
 					tbl.EscapingTableName(), tbl.Name, att.Name, att.EscapingName, targetTable, targetCol))
+				plan.AddForeignKey(tbl, att.Name, targetTable+"("+targetCol+")")
 			} else {
 				t.createAttrs = append(t.createAttrs, fmt.Sprintf("%v %v %v %v,", att.EscapingName, att.DataType, func() string {
 					if att.Nullable {
@@ -240,7 +275,7 @@ func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *stri
 			att := col.OneTo
 			tb := tables[att.TargetTable]
 			if !tb.Migrated && tb != tbl {
-				createTable(tb, dataMap, sql, tables, sqlForeignKeys)
+				createTable(tb, dataMap, sql, tables, sqlForeignKeys, plan)
 			}
 			att.DataType = checkDataType(att.DataType, dataMap).typeName
 			feature := "NULL"
@@ -251,14 +286,16 @@ func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *stri
 			if !att.IsOneToMany {
 				sqlForeignKeys.WriteString(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT uq_%v_%v UNIQUE (%v);\n",
 					tbl.EscapingTableName(), tbl.Name, att.Name, att.EscapingName))
+				plan.ChangeIndex(tbl, fmt.Sprintf("uq_%v_%v", tbl.Name, att.Name), false)
 			}
 			sqlForeignKeys.WriteString(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT fk_%v_%v FOREIGN KEY (%v) REFERENCES %v(%v);\n",
 				tbl.EscapingTableName(), tbl.Name, att.Name, att.EscapingName, att.EscapingTargetTableName(), att.EscapingTargetColumn))
+			plan.AddForeignKey(tbl, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 		} else if col.ManyTo != nil {
 			att := col.ManyTo
 			tb := tables[att.TargetTable]
 			if !tb.Migrated && tb != tbl {
-				createTable(tb, dataMap, sql, tables, sqlForeignKeys)
+				createTable(tb, dataMap, sql, tables, sqlForeignKeys, plan)
 			}
 			att.DataType = checkDataType(att.DataType, dataMap).typeName
 			feature := "NULL"
@@ -268,10 +305,17 @@ func createTable(tbl *model.TableMigrate, dataMap map[string]dataType, sql *stri
 			t.createAttrs = append(t.createAttrs, fmt.Sprintf("%v %v %v,", att.EscapingName, att.DataType, feature))
 			sqlForeignKeys.WriteString(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT fk_%v_%v FOREIGN KEY (%v) REFERENCES %v(%v);\n",
 				tbl.EscapingTableName(), tbl.Name, att.Name, att.EscapingName, att.EscapingTargetTableName(), att.EscapingTargetColumn))
+			plan.AddForeignKey(tbl, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 		}
 	}
 
+	for _, fk := range tbl.ForeignKeys {
+		sqlForeignKeys.WriteString(addFkComposite(tbl, fk))
+		plan.AddForeignKey(tbl, strings.Join(fk.Columns, ","), fk.EscapingTargetTableName()+"("+strings.Join(fk.EscapingTargetColumns, ",")+")")
+	}
+
 	tbl.Migrated = true
+	plan.CreateTable(tbl)
 	t.createPk = fmt.Sprintf("primary key (%v", tbl.PrimaryKeys[0].EscapingName)
 	for _, pk := range tbl.PrimaryKeys[1:] {
 		t.createPk += fmt.Sprintf(",%v", pk.EscapingName)
@@ -300,7 +344,7 @@ type table struct {
 	createAttrs []string
 }
 
-func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *strings.Builder, conn *pgxpool.Pool) error {
+func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *strings.Builder, conn *pgxpool.Pool, plan *model.MigrationPlan) error {
 	schema := "public"
 	if table.Schema != nil && *table.Schema != "" {
 		schema = *table.Schema
@@ -312,17 +356,17 @@ func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *st
 
 	for i := range indexes {
 		if dbIndex, exist := dis[indexes[i].Name]; exist {
-			if indexes[i].Unique != dbIndex.unique {
-				sql.WriteString(dropIndex(table, indexes[i].EscapingName, dbIndex.constraintName))
-				sql.WriteString(createIndex(indexes[i], table))
-			} else if indexes[i].Func != "" && indexes[i].Func != dbIndex.attname {
+			if indexes[i].Unique != dbIndex.unique || indexes[i].Func != "" && indexes[i].Func != dbIndex.attname {
 				sql.WriteString(dropIndex(table, indexes[i].EscapingName, dbIndex.constraintName))
 				sql.WriteString(createIndex(indexes[i], table))
+				plan.ChangeIndex(table, indexes[i].Name, true)
+				plan.ChangeIndex(table, indexes[i].Name, false)
 			}
 			dbIndex.migrated = true
 			continue
 		}
 		sql.WriteString(createIndex(indexes[i], table))
+		plan.ChangeIndex(table, indexes[i].Name, false)
 	}
 
 	for _, dbIndex := range dis {
@@ -332,13 +376,14 @@ func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *st
 			}
 			if !slices.ContainsFunc(table.OneToSomes, isSameName) {
 				sql.WriteString(dropIndex(table, keywordHandler(dbIndex.indexName), dbIndex.constraintName))
+				plan.ChangeIndex(table, dbIndex.indexName, true)
 			}
 		}
 	}
 	return nil
 }
 
-func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate, dataMap map[string]dataType, sql *strings.Builder) {
+func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate, dataMap map[string]dataType, sql *strings.Builder, plan *model.MigrationPlan) {
 	schema := "public"
 	if table.Schema != nil && *table.Schema != "" {
 		schema = *table.Schema
@@ -354,6 +399,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 				dataType = checkTypeAutoIncrement(dataType)
 			}
 			if column.dataType != dataType {
+				plan.AlterColumn(table, att.Name, "type "+column.dataType+" -> "+dataType)
 				if att.AutoIncrement {
 					sql.WriteString(alterColumn(table, att.EscapingName, fmt.Sprintf("%v USING %v::%v", checkTypeAutoIncrement(dataType), att.EscapingName, checkTypeAutoIncrement(dataType)), dataMap))
 					sql.WriteString(fmt.Sprintf("CREATE SEQUENCE %v_%v_seq OWNED BY %v.%v;\n", table.Name, att.Name, table.EscapingTableName(), att.EscapingName))
@@ -364,6 +410,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 			}
 			if !att.AutoIncrement && column.defaultValue != nil {
 				if att.Default == "" {
+					plan.AlterColumn(table, att.Name, "drop default "+*column.defaultValue)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v DROP DEFAULT;",
 						table.EscapingTableName(),
 						att.EscapingName,
@@ -371,6 +418,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 					continue
 				}
 				if !strings.HasPrefix(*column.defaultValue, att.Default) {
+					plan.AlterColumn(table, att.Name, "default "+*column.defaultValue+" -> "+att.Default)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v;",
 						table.EscapingTableName(),
 						att.EscapingName,
@@ -380,6 +428,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 				}
 			}
 			if att.Default != "" && column.defaultValue == nil {
+				plan.AlterColumn(table, att.Name, "set default "+att.Default)
 				sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v;",
 					table.EscapingTableName(),
 					att.EscapingName,
@@ -393,13 +442,16 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 		if column, exist := dbTable.columns[att.Name]; exist {
 			dataType := checkDataType(att.DataType, dataMap).typeName
 			if column.dataType != dataType {
+				plan.AlterColumn(table, att.Name, "type "+column.dataType+" -> "+dataType)
 				sql.WriteString(alterColumn(table, att.EscapingName, dataType, dataMap))
 			}
 			if column.nullable != att.Nullable {
+				plan.AlterColumn(table, att.Name, nullableDetail(att.Nullable))
 				sql.WriteString(nullableColumn(table, att.EscapingName, att.Nullable))
 			}
 			if column.defaultValue != nil {
 				if att.Default == "" {
+					plan.AlterColumn(table, att.Name, "drop default "+*column.defaultValue)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v DROP DEFAULT;",
 						table.EscapingTableName(),
 						att.EscapingName,
@@ -407,6 +459,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 					continue
 				}
 				if *column.defaultValue != att.Default {
+					plan.AlterColumn(table, att.Name, "default "+*column.defaultValue+" -> "+att.Default)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v;",
 						table.EscapingTableName(),
 						att.EscapingName,
@@ -416,6 +469,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 				}
 			}
 			if att.Default != "" && column.defaultValue == nil {
+				plan.AlterColumn(table, att.Name, "set default "+att.Default)
 				sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v;",
 					table.EscapingTableName(),
 					att.EscapingName,
@@ -425,6 +479,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 			continue
 		}
 		dt := checkDataType(att.DataType, dataMap)
+		plan.AddColumn(table, att.Name, dt.typeName)
 		if att.Default != "" {
 			dt.zeroValue = att.Default
 			sql.WriteString(addColumn(table, att.EscapingName, dt, att.Nullable, false))
@@ -441,6 +496,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 				}
 				if !att.IsOneToMany {
 					c := fmt.Sprintf("%v_%v_key", table.Name, column.columnName)
+					plan.ChangeIndex(table, c, false)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v UNIQUE (%v);\n",
 						table.EscapingTableName(),
 						keywordHandler(c),
@@ -448,24 +504,31 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 				}
 			}
 			if column.nullable != att.Nullable {
+				plan.AlterColumn(table, att.Name, nullableDetail(att.Nullable))
 				sql.WriteString(nullableColumn(table, att.EscapingName, att.Nullable))
 			}
 			continue
 		}
-		sql.WriteString(addColumnUnique(table, att.EscapingName, checkDataType(att.DataType, dataMap), att.Nullable))
+		dt := checkDataType(att.DataType, dataMap)
+		plan.AddColumn(table, att.Name, dt.typeName)
+		plan.AddForeignKey(table, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
+		sql.WriteString(addColumnUnique(table, att.EscapingName, dt, att.Nullable))
 		sql.WriteString(addFkOneToSome(table, att))
 	}
 
 	for _, att := range table.ManyToSomes {
 		if column, exist := dbTable.columns[att.Name]; exist {
 			if c, unique := checkFkUnique(conn, schema, table.Name, att.Name); unique {
+				plan.ChangeIndex(table, c, true)
 				sql.WriteString(fmt.Sprintf("ALTER TABLE %v DROP CONSTRAINT %v;\n", table.EscapingTableName(), keywordHandler(c)))
 			}
 			if column.nullable != att.Nullable {
+				plan.AlterColumn(table, att.Name, nullableDetail(att.Nullable))
 				sql.WriteString(nullableColumn(table, att.EscapingName, att.Nullable))
 			}
 			if column.defaultValue != nil {
 				if att.Default == "" {
+					plan.AlterColumn(table, att.Name, "drop default "+*column.defaultValue)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v DROP DEFAULT;",
 						table.EscapingTableName(),
 						att.EscapingName,
@@ -473,6 +536,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 					continue
 				}
 				if *column.defaultValue != att.Default {
+					plan.AlterColumn(table, att.Name, "default "+*column.defaultValue+" -> "+att.Default)
 					sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v;",
 						table.EscapingTableName(),
 						att.EscapingName,
@@ -482,6 +546,7 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 				}
 			}
 			if att.Default != "" && column.defaultValue == nil {
+				plan.AlterColumn(table, att.Name, "set default "+att.Default)
 				sql.WriteString(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v SET DEFAULT %v;",
 					table.EscapingTableName(),
 					att.EscapingName,
@@ -491,6 +556,8 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 			continue
 		}
 		dt := checkDataType(att.DataType, dataMap)
+		plan.AddColumn(table, att.Name, dt.typeName)
+		plan.AddForeignKey(table, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 		if att.Default != "" {
 			dt.zeroValue = att.Default
 			sql.WriteString(addColumn(table, att.EscapingName, dt, att.Nullable, false))
@@ -501,3 +568,10 @@ func checkFields(conn *pgxpool.Pool, dbTable dbTable, table *model.TableMigrate,
 		sql.WriteString(addFkManyToSome(table, att))
 	}
 }
+
+func nullableDetail(nullable bool) string {
+	if nullable {
+		return "NOT NULL -> NULL"
+	}
+	return "NULL -> NOT NULL"
+}
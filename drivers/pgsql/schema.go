@@ -360,6 +360,7 @@ func getTableColumns(conn *pgxpool.Pool, schema, tableName string) (dbTable, err
 	when data_type = 'bigint' then case WHEN column_default like 'nextval%' THEN 'bigserial' ELSE data_type end
 	when data_type like 'timestamp%' then 'timestamp'
 	when data_type like 'numeric' then CONCAT('decimal', '(',numeric_precision, ',', numeric_scale, ')')
+	when data_type = 'ARRAY' then CONCAT(substr(udt_name, 2), '[]')
 	ELSE data_type END,
 	column_default,
 	CASE
@@ -508,6 +509,27 @@ func addFkManyToSome(table *model.TableMigrate, att model.ManyToSomeMigrate) str
 		att.EscapingTargetColumn)
 }
 
+// addFkComposite generates ADD CONSTRAINT FOREIGN KEY SQL for a key spanning several columns.
+func addFkComposite(table *model.TableMigrate, fk model.ForeignKeyMigrate) string {
+	return fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v FOREIGN KEY (%v) REFERENCES %v (%v);\n",
+		table.EscapingTableName(),
+		keywordHandler(fk.Name),
+		strings.Join(fk.EscapingColumns, ", "),
+		fk.EscapingTargetTableName(),
+		strings.Join(fk.EscapingTargetColumns, ", "))
+}
+
+// checkFkComposite reports whether a foreign key constraint exists on a table.
+func checkFkComposite(conn *pgxpool.Pool, schema, table, name string) bool {
+	sql := `SELECT COUNT(*) FROM information_schema.table_constraints
+	WHERE constraint_type = 'FOREIGN KEY' AND table_schema = $1 AND table_name = $2 AND constraint_name = $3;`
+
+	var n int
+	row := conn.QueryRow(context.Background(), sql, schema, table, name)
+	row.Scan(&n)
+	return n > 0
+}
+
 // addFkOneToSome generates ADD CONSTRAINT FOREIGN KEY SQL for one-to-one/one-to-many.
 func addFkOneToSome(table *model.TableMigrate, att model.OneToSomeMigrate) string {
 	c := keywordHandler(fmt.Sprintf("fk_%v_%v", table.Name, att.Name))
@@ -562,6 +584,10 @@ func setDefault(d string) string {
 
 // createIndex generates CREATE INDEX SQL.
 func createIndex(index model.IndexMigrate, table *model.TableMigrate) string {
+	if index.FullText {
+		return fmt.Sprintf("CREATE INDEX %v ON %v USING GIN (to_tsvector('simple', %v));\n",
+			index.EscapingName, table.EscapingTableName(), index.Attributes[0].EscapingName)
+	}
 	return fmt.Sprintf("CREATE %v %v ON %v (%v);\n",
 		func() string {
 			if index.Unique {
@@ -604,6 +630,12 @@ func checkDataType(structDataType string, dataMap map[string]dataType) dataType
 		dt = dataType{"int32", "0"}
 	case "uint64":
 		dt = dataType{"int64", "0"}
+	case "[]int8", "[]uint16":
+		dt = dataType{"[]int16", "'{}'"}
+	case "[]int", "[]uint", "[]uint32":
+		dt = dataType{"[]int32", "'{}'"}
+	case "[]uint64":
+		dt = dataType{"[]int64", "'{}'"}
 	}
 
 	if dt, ok := dataMap[dt.typeName]; ok {
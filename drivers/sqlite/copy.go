@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+const (
+	copyChunkRows = 500   // rows inserted by one prepared statement
+	copyMaxParams = 32766 // SQLITE_MAX_VARIABLE_NUMBER of SQLite 3.32+
+)
+
+// CopyFrom inserts the rows in chunks of prepared multi-row INSERTs inside one transaction.
+func (c Connection) CopyFrom(ctx context.Context, schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	tx, err := c.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	n, err := copyRows(ctx, tx, table, columns, rows)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// CopyFrom inserts the rows in chunks of prepared multi-row INSERTs inside the transaction.
+func (t Transaction) CopyFrom(ctx context.Context, schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	return copyRows(ctx, t.tx, table, columns, rows)
+}
+
+// copyRows runs the same prepared statement for every full chunk, and one more INSERT for the rest.
+func copyRows(ctx context.Context, tx *sql.Tx, table string, columns []string, rows iter.Seq[[]any]) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("goent: copy into %s without columns", table)
+	}
+	size := max(1, min(copyChunkRows, copyMaxParams/len(columns)))
+	stmt, err := tx.PrepareContext(ctx, copyInsertSql(table, columns, size))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var n int64
+	args := make([]any, 0, size*len(columns))
+	for row := range rows {
+		if len(row) != len(columns) {
+			return n, fmt.Errorf("goent: copy into %s: row has %d values, want %d", table, len(row), len(columns))
+		}
+		args = append(args, row...)
+		if len(args) < cap(args) {
+			continue
+		}
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			return n, err
+		}
+		n += int64(size)
+		args = args[:0]
+	}
+	if rest := len(args) / len(columns); rest > 0 {
+		if _, err = tx.ExecContext(ctx, copyInsertSql(table, columns, rest), args...); err != nil {
+			return n, err
+		}
+		n += int64(rest)
+	}
+	return n, nil
+}
+
+// copyInsertSql returns an INSERT of the given number of rows.
+func copyInsertSql(table string, columns []string, rows int) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = keywordHandler(col)
+	}
+	values := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	b.WriteString(keywordHandler(table))
+	b.WriteString(" (")
+	b.WriteString(strings.Join(quoted, ", "))
+	b.WriteString(") VALUES ")
+	for i := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(values)
+	}
+	return b.String()
+}
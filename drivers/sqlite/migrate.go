@@ -21,6 +21,7 @@ type body struct {
 	sql     *strings.Builder
 	conn    *sql.DB
 	tables  map[string]*model.TableMigrate
+	plan    *model.MigrationPlan
 	dbTable
 }
 
@@ -36,6 +37,18 @@ func (dr *Driver) MigrateContext(ctx context.Context, migrator *model.Migrator)
 		"time.Time": {"datetime", "'0000-01-01'"},
 		"bool":      {"boolean", "false"},
 		"uuid.UUID": {"uuid", "'00000000-0000-0000-0000-000000000000'"},
+		"json":      {"text", "'null'"},
+		"[]int16":   {"text", "'[]'"},
+		"[]int32":   {"text", "'[]'"},
+		"[]int64":   {"text", "'[]'"},
+		"[]float32": {"text", "'[]'"},
+		"[]float64": {"text", "'[]'"},
+		"[]string":  {"text", "'[]'"},
+		"[]bool":    {"text", "'[]'"},
+		"int4range": {"text", "'{}'"},
+		"int8range": {"text", "'{}'"},
+		"numrange":  {"text", "'{}'"},
+		"tsrange":   {"text", "'{}'"},
 	}
 
 	sql := new(strings.Builder)
@@ -50,12 +63,13 @@ func (dr *Driver) MigrateContext(ctx context.Context, migrator *model.Migrator)
 			sql:     sql,
 			conn:    dr.sql,
 			tables:  migrator.Tables,
+			plan:    migrator.Plan,
 		})
 		if err != nil {
 			return err
 		}
 
-		err = checkIndex(t.Indexes, t, sqlColumns, dr.sql)
+		err = checkIndex(t.Indexes, t, sqlColumns, dr.sql, migrator.Plan)
 		if err != nil {
 			return err
 		}
@@ -63,16 +77,20 @@ func (dr *Driver) MigrateContext(ctx context.Context, migrator *model.Migrator)
 
 	for _, t := range migrator.Tables {
 		if !t.Migrated {
-			createTable(t, dataMap, sql, migrator.Tables, false)
+			createTable(t, dataMap, sql, migrator.Tables, false, migrator.Plan)
 		}
 	}
 
 	sql.WriteString(sqlColumns.String())
 
-	if sql.Len() != 0 {
-		return dr.rawExecContext(ctx, sql.String())
+	if sql.Len() == 0 {
+		return nil
 	}
-	return nil
+	if migrator.Plan != nil {
+		migrator.Plan.Sql = sql.String()
+		return nil
+	}
+	return dr.rawExecContext(ctx, sql.String())
 }
 
 func (dr *Driver) rawExecContext(ctx context.Context, rawSql string, args ...any) error {
@@ -161,7 +179,7 @@ func checkTableChanges(b body) error {
 	return nil
 }
 
-func createTable(tbl *model.TableMigrate, dataMap map[string]*dataType, sql *strings.Builder, tables map[string]*model.TableMigrate, skipDependency bool) {
+func createTable(tbl *model.TableMigrate, dataMap map[string]*dataType, sql *strings.Builder, tables map[string]*model.TableMigrate, skipDependency bool, plan *model.MigrationPlan) {
 	t := table{}
 	t.name = fmt.Sprintf("CREATE TABLE %v (", tbl.EscapingTableName())
 	processedAttrs := make(map[string]bool)
@@ -193,30 +211,41 @@ func createTable(tbl *model.TableMigrate, dataMap map[string]*dataType, sql *str
 			}(), setDefault(att.Default)))
 		} else if col.OneTo != nil {
 			att := col.OneTo
+			plan.AddForeignKey(tbl, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 			tb := tables[att.TargetTable]
 			if tb.Migrated {
 				t.createAttrs = append(t.createAttrs, foreignOneToSome(*att, dataMap))
 			} else {
 				if tb != tbl && !skipDependency {
-					createTable(tb, dataMap, sql, tables, false)
+					createTable(tb, dataMap, sql, tables, false, plan)
 				}
 				t.createAttrs = append(t.createAttrs, foreignOneToSome(*att, dataMap))
 			}
 		} else if col.ManyTo != nil {
 			att := col.ManyTo
+			plan.AddForeignKey(tbl, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 			tb := tables[att.TargetTable]
 			if tb.Migrated {
 				t.createAttrs = append(t.createAttrs, foreignManyToSome(*att, dataMap))
 			} else {
 				if tb != tbl && !skipDependency {
-					createTable(tb, dataMap, sql, tables, false)
+					createTable(tb, dataMap, sql, tables, false, plan)
 				}
 				t.createAttrs = append(t.createAttrs, foreignManyToSome(*att, dataMap))
 			}
 		}
 	}
 
+	for _, fk := range tbl.ForeignKeys {
+		plan.AddForeignKey(tbl, strings.Join(fk.Columns, ","), fk.EscapingTargetTable+"("+strings.Join(fk.EscapingTargetColumns, ",")+")")
+		if tb := tables[fk.TargetTable]; tb != nil && !tb.Migrated && tb != tbl && !skipDependency {
+			createTable(tb, dataMap, sql, tables, false, plan)
+		}
+		t.createAttrs = append(t.createAttrs, foreignComposite(fk))
+	}
+
 	tbl.Migrated = true
+	plan.CreateTable(tbl)
 	t.createPk = fmt.Sprintf("primary key (%v", tbl.PrimaryKeys[0].EscapingName)
 	for _, pk := range tbl.PrimaryKeys[1:] {
 		t.createPk += fmt.Sprintf(",%v", pk.EscapingName)
@@ -231,25 +260,31 @@ type table struct {
 	createAttrs []string
 }
 
-func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *strings.Builder, conn *sql.DB) error {
+func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *strings.Builder, conn *sql.DB, plan *model.MigrationPlan) error {
 	dis, err := getTableIndexes(conn, table.Name)
 	if err != nil {
 		return err
 	}
 
+	var fullText []model.AttributeMigrate
 	for i := range indexes {
+		if indexes[i].FullText {
+			fullText = append(fullText, indexes[i].Attributes...)
+			continue
+		}
 		if dbIndex, exist := dis[indexes[i].Name]; exist {
-			if indexes[i].Unique != dbIndex.unique {
-				sql.WriteString(dropIndex(table, indexes[i].EscapingName))
-				sql.WriteString(createIndex(indexes[i], table))
-			} else if indexes[i].Func != "" && !strings.Contains(regexp.MustCompile(`(?:\()[a-z]+`).FindString(dbIndex.sql), indexes[i].Func) {
+			if indexes[i].Unique != dbIndex.unique ||
+				indexes[i].Func != "" && !strings.Contains(regexp.MustCompile(`(?:\()[a-z]+`).FindString(dbIndex.sql), indexes[i].Func) {
 				sql.WriteString(dropIndex(table, indexes[i].EscapingName))
 				sql.WriteString(createIndex(indexes[i], table))
+				plan.ChangeIndex(table, indexes[i].Name, true)
+				plan.ChangeIndex(table, indexes[i].Name, false)
 			}
 			dbIndex.migrated = true
 			continue
 		}
 		sql.WriteString(createIndex(indexes[i], table))
+		plan.ChangeIndex(table, indexes[i].Name, false)
 	}
 
 	for _, dbIndex := range dis {
@@ -259,12 +294,94 @@ func checkIndex(indexes []model.IndexMigrate, table *model.TableMigrate, sql *st
 		if !dbIndex.migrated {
 			if !slices.ContainsFunc(table.OneToSomes, isSameName) {
 				sql.WriteString(fmt.Sprintf("DROP INDEX IF EXISTS %v;", keywordHandler(dbIndex.indexName)) + "\n")
+				plan.ChangeIndex(table, dbIndex.indexName, true)
 			}
 		}
 	}
+	return checkFullText(fullText, table, sql, conn, plan)
+}
+
+// checkFullText keeps the FTS5 table of the goe:"fulltext" columns, an external content table
+// of the table kept in sync by triggers. It creates the table and its triggers when they do not
+// exist yet, e.g. the table was dropped, rebuilds them when the columns changed and drops them
+// when the table has no goe:"fulltext" column any more.
+func checkFullText(attrs []model.AttributeMigrate, table *model.TableMigrate, sql *strings.Builder, conn *sql.DB, plan *model.MigrationPlan) error {
+	name := table.Name + "_fts"
+	fts := keywordHandler(name)
+	triggers := []string{name + "_ai", name + "_ad", name + "_au"}
+	dbColumns, dbTriggers, err := getFullText(conn, name, triggers)
+	if err != nil {
+		return err
+	}
+	columns, names := make([]string, len(attrs)), make([]string, len(attrs))
+	for i, att := range attrs {
+		columns[i], names[i] = att.EscapingName, att.Name
+	}
+	if slices.Equal(dbColumns, names) && dbTriggers == len(triggers) {
+		return nil
+	}
+	if len(dbColumns) > 0 || dbTriggers > 0 {
+		for _, trigger := range triggers {
+			sql.WriteString(fmt.Sprintf("DROP TRIGGER IF EXISTS %v;\n", keywordHandler(trigger)))
+		}
+		sql.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %v;\n", fts))
+		plan.ChangeIndex(table, name, true)
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	if len(table.PrimaryKeys) != 1 || !isIntegerType(table.PrimaryKeys[0].DataType) {
+		return fmt.Errorf("goent: full-text search of table %v needs a single integer primary key", table.Name)
+	}
+
+	pk := table.PrimaryKeys[0].EscapingName
+	values := func(prefix string) string {
+		return prefix + strings.Join(columns, ", "+prefix)
+	}
+	cols := strings.Join(columns, ", ")
+	sql.WriteString(fmt.Sprintf("CREATE VIRTUAL TABLE %v USING fts5(%v, content=%v, content_rowid=%v);\n",
+		fts, cols, table.EscapingName, pk))
+	sql.WriteString(fmt.Sprintf("CREATE TRIGGER %v AFTER INSERT ON %v BEGIN INSERT INTO %v(rowid, %v) VALUES (new.%v, %v); END;\n",
+		keywordHandler(triggers[0]), table.EscapingName, fts, cols, pk, values("new.")))
+	sql.WriteString(fmt.Sprintf("CREATE TRIGGER %v AFTER DELETE ON %v BEGIN INSERT INTO %v(%v, rowid, %v) VALUES ('delete', old.%v, %v); END;\n",
+		keywordHandler(triggers[1]), table.EscapingName, fts, fts, cols, pk, values("old.")))
+	sql.WriteString(fmt.Sprintf("CREATE TRIGGER %v AFTER UPDATE ON %v BEGIN INSERT INTO %v(%v, rowid, %v) VALUES ('delete', old.%v, %v); "+
+		"INSERT INTO %v(rowid, %v) VALUES (new.%v, %v); END;\n",
+		keywordHandler(triggers[2]), table.EscapingName, fts, fts, cols, pk, values("old."), fts, cols, pk, values("new.")))
+	sql.WriteString(fmt.Sprintf("INSERT INTO %v(%v) VALUES ('rebuild');\n", fts, fts))
+	plan.ChangeIndex(table, name, false)
 	return nil
 }
 
+// getFullText returns the columns of a FTS5 table, in order, and how many of its triggers exist.
+func getFullText(conn *sql.DB, name string, triggers []string) (columns []string, count int, err error) {
+	ctx := context.Background()
+	rows, err := conn.QueryContext(ctx, "SELECT name FROM pragma_table_info($1) ORDER BY cid", name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column string
+		if err = rows.Scan(&column); err != nil {
+			return nil, 0, err
+		}
+		columns = append(columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	err = conn.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ($1, $2, $3)",
+		triggers[0], triggers[1], triggers[2]).Scan(&count)
+	return columns, count, err
+}
+
+// isIntegerType returns true for the signed and unsigned integer types of the struct fields,
+// which SQLite can use as the rowid of a FTS5 table.
+func isIntegerType(dataType string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(dataType, "u"), "int")
+}
+
 func checkFields(b body) {
 	var alter bool
 	for _, att := range b.table.PrimaryKeys {
@@ -276,20 +393,24 @@ func checkFields(b body) {
 
 			dataType := checkDataType(att.DataType, b.dataMap).typeName
 			if column.dataType != dataType {
+				b.plan.AlterColumn(b.table, att.Name, "type "+column.dataType+" -> "+dataType)
 				alter = true
 				break
 			}
 			if !att.AutoIncrement && column.defaultValue != nil {
 				if att.Default == "" {
+					b.plan.AlterColumn(b.table, att.Name, "drop default "+*column.defaultValue)
 					alter = true
 					break
 				}
 				if *column.defaultValue != att.Default {
+					b.plan.AlterColumn(b.table, att.Name, "default "+*column.defaultValue+" -> "+att.Default)
 					alter = true
 					break
 				}
 			}
 			if att.Default != "" && column.defaultValue == nil {
+				b.plan.AlterColumn(b.table, att.Name, "set default "+att.Default)
 				alter = true
 				break
 			}
@@ -303,15 +424,19 @@ func checkFields(b body) {
 				if foreignKeyIsPrimarykey(b.table, att.Name) {
 					continue
 				}
+				b.plan.AlterColumn(b.table, att.Name, "add unique")
 				alter = true
 				break
 			}
 			if column.nullable != att.Nullable {
+				b.plan.AlterColumn(b.table, att.Name, nullableDetail(att.Nullable))
 				alter = true
 				break
 			}
 			continue
 		}
+		b.plan.AddColumn(b.table, att.Name, checkDataType(att.DataType, b.dataMap).typeName)
+		b.plan.AddForeignKey(b.table, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 		alter = true
 		break
 	}
@@ -320,56 +445,73 @@ func checkFields(b body) {
 		if column, exist := b.dbTable.columns[att.Name]; exist {
 			column.migrated = true
 			if unique := checkFkUnique(b.conn, b.table.Name, att.Name); unique {
+				b.plan.AlterColumn(b.table, att.Name, "drop unique")
 				alter = true
 				break
 			}
 			if column.nullable != att.Nullable {
+				b.plan.AlterColumn(b.table, att.Name, nullableDetail(att.Nullable))
 				alter = true
 				break
 			}
 			continue
 		}
+		b.plan.AddColumn(b.table, att.Name, checkDataType(att.DataType, b.dataMap).typeName)
+		b.plan.AddForeignKey(b.table, att.Name, att.EscapingTargetTableName()+"("+att.EscapingTargetColumn+")")
 		alter = true
 		break
 	}
 
+	for _, fk := range b.table.ForeignKeys {
+		if !checkFkComposite(b.conn, b.table.Name, fk.Columns) {
+			b.plan.AddForeignKey(b.table, strings.Join(fk.Columns, ","), fk.EscapingTargetTable+"("+strings.Join(fk.EscapingTargetColumns, ",")+")")
+			alter = true
+		}
+	}
+
 	var newColumns []string
 	for _, att := range b.table.Attributes {
 		if column, exist := b.dbTable.columns[att.Name]; exist {
 			column.migrated = true
 			dataType := checkDataType(att.DataType, b.dataMap).typeName
 			if column.dataType != dataType {
+				b.plan.AlterColumn(b.table, att.Name, "type "+column.dataType+" -> "+dataType)
 				alter = true
 			}
 			if column.nullable != att.Nullable {
+				b.plan.AlterColumn(b.table, att.Name, nullableDetail(att.Nullable))
 				alter = true
 			}
 			if column.defaultValue != nil {
-				if att.Default == "" {
-					alter = true
-				}
 				defaultExpr := setDefault(att.Default)
 				defaultSuffix := ""
 				if len(defaultExpr) >= 8 {
 					defaultSuffix = defaultExpr[8:]
 				}
-				if *column.defaultValue != defaultSuffix {
+				if att.Default == "" {
+					b.plan.AlterColumn(b.table, att.Name, "drop default "+*column.defaultValue)
+					alter = true
+				} else if *column.defaultValue != defaultSuffix {
+					b.plan.AlterColumn(b.table, att.Name, "default "+*column.defaultValue+" -> "+att.Default)
 					alter = true
 				}
 			}
 			if att.Default != "" && column.defaultValue == nil {
+				b.plan.AlterColumn(b.table, att.Name, "set default "+att.Default)
 				alter = true
 			}
 			continue
 		}
-		newColumns = append(newColumns, addColumn(b.table, att.EscapingName, checkDataType(att.DataType, b.dataMap), att.Nullable))
+		dt := checkDataType(att.DataType, b.dataMap)
+		b.plan.AddColumn(b.table, att.Name, dt.typeName)
+		newColumns = append(newColumns, addColumn(b.table, att.EscapingName, dt, att.Nullable))
 		alter = true
 	}
 
 	for _, c := range b.dbTable.columns {
 		if !c.migrated {
+			b.plan.DropColumn(b.table, c.columnName)
 			alter = true
-			break
 		}
 	}
 
@@ -390,7 +532,7 @@ func alterSqlite(b body) {
 
 	insertColumns, selectExprs := tableAttributesWithDB(b)
 	sqlBuilder.WriteString("BEGIN TRANSACTION; PRAGMA foreign_keys=OFF; \n")
-	createTable(&newTable, b.dataMap, sqlBuilder, b.tables, true)
+	createTable(&newTable, b.dataMap, sqlBuilder, b.tables, true, nil)
 	sqlBuilder.WriteString(
 		fmt.Sprintf("INSERT INTO %v (%v) SELECT %v FROM %v;\n",
 			newTable.EscapingTableName(),
@@ -400,6 +542,7 @@ func alterSqlite(b body) {
 	sqlBuilder.WriteString("DROP TABLE " + b.table.EscapingTableName() + ";\n")
 	sqlBuilder.WriteString(fmt.Sprintf("ALTER TABLE %v RENAME TO %v;\n", newTable.EscapingTableName(), b.table.EscapingName))
 	sqlBuilder.WriteString("PRAGMA foreign_keys=ON; COMMIT;")
+	b.plan.RebuildTable(b.table)
 
 	b.sql.WriteString(sqlBuilder.String())
 }
@@ -411,8 +554,14 @@ func tableAttributesWithDB(b body) (string, string) {
 	insertCols := &strings.Builder{}
 	selectExprs := &strings.Builder{}
 
-	// Helper to append a column pair
-	appendPair := func(modelColName string, dbColName string, defaultVal string) {
+	// Helper to append a column pair, new columns without a default take the zero value of their type
+	appendPair := func(modelColName string, dbColName string, defaultVal string, dataType string) {
+		if defaultVal == "" {
+			defaultVal = checkDataType(dataType, b.dataMap).zeroValue
+		}
+		if defaultVal == "" {
+			defaultVal = "NULL"
+		}
 		if insertCols.Len() > 0 {
 			insertCols.WriteString(",")
 			selectExprs.WriteString(",")
@@ -432,7 +581,7 @@ func tableAttributesWithDB(b body) (string, string) {
 		if c, ok := b.dbTable.columns[p.Name]; ok {
 			dbColName = c.columnName
 		}
-		appendPair(p.EscapingName, dbColName, setDefault(p.Default))
+		appendPair(p.EscapingName, dbColName, p.Default, p.DataType)
 	}
 
 	// Attributes
@@ -441,7 +590,7 @@ func tableAttributesWithDB(b body) (string, string) {
 		if c, ok := b.dbTable.columns[a.Name]; ok {
 			dbColName = c.columnName
 		}
-		appendPair(a.EscapingName, dbColName, setDefault(a.Default))
+		appendPair(a.EscapingName, dbColName, a.Default, a.DataType)
 	}
 
 	// OneToSomes
@@ -450,7 +599,7 @@ func tableAttributesWithDB(b body) (string, string) {
 		if c, ok := b.dbTable.columns[a.Name]; ok {
 			dbColName = c.columnName
 		}
-		appendPair(a.EscapingName, dbColName, setDefault(a.Default))
+		appendPair(a.EscapingName, dbColName, a.Default, a.DataType)
 	}
 
 	// ManyToSomes
@@ -459,7 +608,7 @@ func tableAttributesWithDB(b body) (string, string) {
 		if c, ok := b.dbTable.columns[a.Name]; ok {
 			dbColName = c.columnName
 		}
-		appendPair(a.EscapingName, dbColName, setDefault(a.Default))
+		appendPair(a.EscapingName, dbColName, a.Default, a.DataType)
 	}
 
 	return insertCols.String(), selectExprs.String()
@@ -484,3 +633,10 @@ func tableAttributes(t *model.TableMigrate) (string, string) {
 
 	return newColumns, newColumns
 }
+
+func nullableDetail(nullable bool) string {
+	if nullable {
+		return "NOT NULL -> NULL"
+	}
+	return "NULL -> NOT NULL"
+}
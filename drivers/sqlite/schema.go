@@ -398,6 +398,32 @@ func getTableIndexes(conn *sql.DB, tableName string) (map[string]*databaseIndex,
 	return dis, nil
 }
 
+// checkFkComposite reports whether a table has a foreign key on exactly these columns.
+func checkFkComposite(conn *sql.DB, table string, columns []string) bool {
+	sql := `SELECT id, "from" FROM pragma_foreign_key_list($1) ORDER BY id, seq;`
+
+	rows, err := conn.QueryContext(context.Background(), sql, table)
+	if err != nil {
+		return false
+	}
+	defer rows.Close()
+	keys := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var column string
+		if err = rows.Scan(&id, &column); err != nil {
+			return false
+		}
+		keys[id] = append(keys[id], column)
+	}
+	for _, cols := range keys {
+		if slices.Equal(cols, columns) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkFkUnique checks if a foreign key column has a unique constraint.
 func checkFkUnique(conn *sql.DB, table, attribute string) bool {
 	sql := `
@@ -446,6 +472,12 @@ func checkDataType(structDataType string, dataMap map[string]*dataType) dataType
 		dt = dataType{"int32", "0"}
 	case "uint64":
 		dt = dataType{"int64", "0"}
+	case "[]int8", "[]uint16":
+		dt = dataType{"[]int16", "'{}'"}
+	case "[]int", "[]uint", "[]uint32":
+		dt = dataType{"[]int32", "'{}'"}
+	case "[]uint64":
+		dt = dataType{"[]int64", "'{}'"}
 	case "[16]uint8":
 		dt = dataType{"uuid", "'00000000-0000-0000-0000-000000000000'"}
 	}
@@ -564,6 +596,12 @@ func foreignManyToSome(att model.ManyToSomeMigrate, dataMap map[string]*dataType
 		att.EscapingTargetTable, att.EscapingTargetColumn)
 }
 
+// foreignComposite generates the table constraint of a foreign key spanning several columns.
+func foreignComposite(fk model.ForeignKeyMigrate) string {
+	return fmt.Sprintf("FOREIGN KEY (%v) REFERENCES %v(%v),",
+		strings.Join(fk.EscapingColumns, ", "), fk.EscapingTargetTable, strings.Join(fk.EscapingTargetColumns, ", "))
+}
+
 // foreignOneToSome generates column definition with FK for one-to-one/one-to-many.
 func foreignOneToSome(att model.OneToSomeMigrate, dataMap map[string]*dataType) string {
 	att.DataType = checkDataType(att.DataType, dataMap).typeName
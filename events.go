@@ -55,7 +55,8 @@ type EventData struct {
 	Model    string         // Go struct type name (e.g. "Animal")
 	Table    string         // database table name (e.g. "animals")
 	Where    string         // WHERE clause template string (empty for insert/bypk)
-	IDs      []int64        // affected primary key IDs
+	IDs      []int64        // affected primary key IDs (nil for non-integer keys)
+	Keys     []any          // affected primary key values of any type (nil if only IDs are known)
 	Changes  map[string]any // column changes map (nil for bulk insert and deletes)
 	Affected int64          // number of affected rows
 	TransNo  string         // transaction identifier string (empty if not in a transaction)
@@ -89,6 +90,9 @@ func (e *EventData) ToMap() map[string]any {
 	if e.Changes != nil {
 		data["changes"] = e.Changes
 	}
+	if e.Keys != nil {
+		data["keys"] = e.Keys
+	}
 	return data
 }
 
@@ -102,3 +106,15 @@ func publishEvent(bus *gobus.EventBus, info *TableInfo, conn model.Connection, t
 	data := buildEventData(info, conn, topic, where, ids, changes, affecteds)
 	_, _ = bus.Publish(topic, data.ToMap(), EventPriority, false)
 }
+
+// publishKeysEvent publishes a table modification event for primary keys of any type.
+// Integer keys are also reported as IDs, so subscribers of int64 IDs keep working.
+func publishKeysEvent(bus *gobus.EventBus, info *TableInfo, conn model.Connection, topic, where string,
+	keys []any, changes map[string]any, affecteds int64) {
+	if bus == nil || info == nil || !info.isWatched {
+		return
+	}
+	data := buildEventData(info, conn, topic, where, int64Keys(keys), changes, affecteds)
+	data.Keys = keys
+	_, _ = bus.Publish(topic, data.ToMap(), EventPriority, false)
+}
@@ -124,8 +124,9 @@ func CalcTotalPrice(db *models.Database, order *models.Order) (float64, error) {
 		return 0.0, err
 	}
 
-	filter := goent.Equals(db.OrderDetail.Field("order_id"), order.ID)
-	query := db.OrderDetail.Select().OrderBy("product_id")
+	detail := db.C().OrderDetail
+	filter := detail.OrderID.Equals(order.ID)
+	query := db.OrderDetail.Select().OrderByCols(detail.ProductID)
 	order.Details, err = query.Filter(filter).All()
 	if err != nil {
 		return 0.0, err
@@ -166,8 +167,9 @@ func CalcTotalPrice2(db *models.Database, order *models.Order) (float64, error)
 		return 0.0, err
 	}
 
-	filter := goent.Equals(db.OrderDetail.Field("order_id"), order.ID)
-	query := db.OrderDetail.Select().OrderBy("product_id").Filter(filter)
+	detail := db.C().OrderDetail
+	filter := detail.OrderID.Equals(order.ID)
+	query := db.OrderDetail.Select().OrderByCols(detail.ProductID).Filter(filter)
 	order.Details, err = query.LeftJoin("product_id", db.Product.Field("id")).All()
 	if err != nil {
 		return 0.0, err
@@ -4,7 +4,11 @@
 
 package models
 
-import "github.com/azhai/goent"
+import (
+	"time"
+
+	"github.com/azhai/goent"
+)
 
 // ------------------------------
 // Book
@@ -52,6 +56,32 @@ func FetchBook() goent.FetchFunc {
 	}
 }
 
+// BookColumns holds the typed column references of Book.
+type BookColumns struct {
+	ID           goent.Col[int]
+	Isbn         goent.Col[string]
+	Title        goent.Col[string]
+	Author       goent.Col[string]
+	Genre        goent.Col[string]
+	Quantity     goent.Col[int]
+	PublicizedAt goent.Col[time.Time]
+}
+
+// BookCols returns the typed column references of the Book table, built once per table.
+func BookCols(t *goent.Table[Book]) *BookColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *BookColumns {
+		return &BookColumns{
+			ID:           goent.NewCol[int](info, "id"),
+			Isbn:         goent.NewCol[string](info, "isbn"),
+			Title:        goent.NewCol[string](info, "title"),
+			Author:       goent.NewCol[string](info, "author"),
+			Genre:        goent.NewCol[string](info, "genre"),
+			Quantity:     goent.NewCol[int](info, "quantity"),
+			PublicizedAt: goent.NewCol[time.Time](info, "publicized_at"),
+		}
+	})
+}
+
 // ------------------------------
 // Category
 // ------------------------------
@@ -88,6 +118,22 @@ func FetchCategory() goent.FetchFunc {
 	}
 }
 
+// CategoryColumns holds the typed column references of Category.
+type CategoryColumns struct {
+	ID   goent.Col[int64]
+	Name goent.Col[string]
+}
+
+// CategoryCols returns the typed column references of the Category table, built once per table.
+func CategoryCols(t *goent.Table[Category]) *CategoryColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *CategoryColumns {
+		return &CategoryColumns{
+			ID:   goent.NewCol[int64](info, "id"),
+			Name: goent.NewCol[string](info, "name"),
+		}
+	})
+}
+
 // ------------------------------
 // Order
 // ------------------------------
@@ -132,6 +178,30 @@ func FetchOrder() goent.FetchFunc {
 	}
 }
 
+// OrderColumns holds the typed column references of Order.
+type OrderColumns struct {
+	ID       goent.Col[int64]
+	OrderNo  goent.Col[string]
+	Customer goent.Col[string]
+	Total    goent.Col[float64]
+	Status   goent.Col[string]
+	Created  goent.Col[time.Time]
+}
+
+// OrderCols returns the typed column references of the Order table, built once per table.
+func OrderCols(t *goent.Table[Order]) *OrderColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *OrderColumns {
+		return &OrderColumns{
+			ID:       goent.NewCol[int64](info, "id"),
+			OrderNo:  goent.NewCol[string](info, "order_no"),
+			Customer: goent.NewCol[string](info, "customer"),
+			Total:    goent.NewCol[float64](info, "total"),
+			Status:   goent.NewCol[string](info, "status"),
+			Created:  goent.NewCol[time.Time](info, "created"),
+		}
+	})
+}
+
 // ------------------------------
 // OrderDetail
 // ------------------------------
@@ -153,6 +223,26 @@ func FetchOrderDetail() goent.FetchFunc {
 	}
 }
 
+// OrderDetailColumns holds the typed column references of OrderDetail.
+type OrderDetailColumns struct {
+	OrderID   goent.Col[int64]
+	ProductID goent.Col[int64]
+	Quantity  goent.Col[int]
+	Price     goent.Col[float64]
+}
+
+// OrderDetailCols returns the typed column references of the OrderDetail table, built once per table.
+func OrderDetailCols(t *goent.Table[OrderDetail]) *OrderDetailColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *OrderDetailColumns {
+		return &OrderDetailColumns{
+			OrderID:   goent.NewCol[int64](info, "order_id"),
+			ProductID: goent.NewCol[int64](info, "product_id"),
+			Quantity:  goent.NewCol[int](info, "quantity"),
+			Price:     goent.NewCol[float64](info, "price"),
+		}
+	})
+}
+
 // ------------------------------
 // PricePolicy
 // ------------------------------
@@ -195,6 +285,28 @@ func FetchPricePolicy() goent.FetchFunc {
 	}
 }
 
+// PricePolicyColumns holds the typed column references of PricePolicy.
+type PricePolicyColumns struct {
+	ID        goent.Col[int]
+	BookID    goent.Col[int]
+	Price     goent.Col[float64]
+	StartDate goent.Col[time.Time]
+	EndDate   goent.Col[time.Time]
+}
+
+// PricePolicyCols returns the typed column references of the PricePolicy table, built once per table.
+func PricePolicyCols(t *goent.Table[PricePolicy]) *PricePolicyColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *PricePolicyColumns {
+		return &PricePolicyColumns{
+			ID:        goent.NewCol[int](info, "id"),
+			BookID:    goent.NewCol[int](info, "book_id"),
+			Price:     goent.NewCol[float64](info, "price"),
+			StartDate: goent.NewCol[time.Time](info, "start_date"),
+			EndDate:   goent.NewCol[time.Time](info, "end_date"),
+		}
+	})
+}
+
 // ------------------------------
 // Product
 // ------------------------------
@@ -236,3 +348,51 @@ func FetchProduct() goent.FetchFunc {
 		return target.(*Product).ScanDest()
 	}
 }
+
+// ProductColumns holds the typed column references of Product.
+type ProductColumns struct {
+	ID         goent.Col[int64]
+	CategoryID goent.Col[int64]
+	Name       goent.Col[string]
+	Color      goent.Col[string]
+	Price      goent.Col[float64]
+}
+
+// ProductCols returns the typed column references of the Product table, built once per table.
+func ProductCols(t *goent.Table[Product]) *ProductColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *ProductColumns {
+		return &ProductColumns{
+			ID:         goent.NewCol[int64](info, "id"),
+			CategoryID: goent.NewCol[int64](info, "category_id"),
+			Name:       goent.NewCol[string](info, "name"),
+			Color:      goent.NewCol[string](info, "color"),
+			Price:      goent.NewCol[float64](info, "price"),
+		}
+	})
+}
+
+// ------------------------------
+// Database
+// ------------------------------
+
+// DatabaseColumns holds the typed column references of the tables of Database.
+type DatabaseColumns struct {
+	Book        *BookColumns
+	PricePolicy *PricePolicyColumns
+	Category    *CategoryColumns
+	Order       *OrderColumns
+	OrderDetail *OrderDetailColumns
+	Product     *ProductColumns
+}
+
+// C returns the typed column references of the tables of Database.
+func (d *Database) C() DatabaseColumns {
+	return DatabaseColumns{
+		Book:        BookCols(d.PublicSchema.Book),
+		PricePolicy: PricePolicyCols(d.PublicSchema.PricePolicy),
+		Category:    CategoryCols(d.PublicSchema.Category),
+		Order:       OrderCols(d.PublicSchema.Order),
+		OrderDetail: OrderDetailCols(d.PublicSchema.OrderDetail),
+		Product:     ProductCols(d.PublicSchema.Product),
+	}
+}
@@ -13,6 +13,8 @@ type Field struct {
 	ColumnName string  // Database column name
 	AliasName  string  // Alias name for the field
 	Function   string  // SQL function to apply to the field
+	window     *Window // OVER clause of a window function
+	isJSON     bool    // The column is tagged with goe:"json"
 }
 
 // SameTable checks if two fields belong to the same table
@@ -51,10 +53,14 @@ func (f *Field) GetFid() int {
 // Simple returns the column name with any SQL function applied
 // It does not include the table name
 func (f *Field) Simple() string {
+	res := f.ColumnName
 	if f.Function != "" {
-		return fmt.Sprintf(f.Function, f.ColumnName)
+		res = fmt.Sprintf(f.Function, res)
 	}
-	return f.ColumnName
+	if f.window != nil {
+		res += f.window.over(true)
+	}
+	return res
 }
 
 // String returns the qualified field name (table.column) with any SQL function applied
@@ -65,7 +71,10 @@ func (f *Field) String() string {
 		return ""
 	}
 	if f.Function != "" {
-		return fmt.Sprintf(f.Function, res)
+		res = fmt.Sprintf(f.Function, res)
+	}
+	if f.window != nil {
+		res += f.window.over(false)
 	}
 	return res
 }
@@ -73,9 +82,10 @@ func (f *Field) String() string {
 // Value represents a value or list of values for use in query conditions
 // It handles both single values and slices for IN conditions
 type Value struct {
-	Args   []any // Slice of values for IN conditions
-	Length int   // Length of the value slice
-	single any   // Single value storage to avoid slice allocation
+	Args   []any    // Slice of values for IN conditions
+	Length int      // Length of the value slice
+	single any      // Single value storage to avoid slice allocation
+	sub    *Builder // Subquery built in place of a placeholder
 }
 
 // NewValue creates a new Value from a Go value
@@ -91,6 +101,8 @@ func NewValue(value any) *Value {
 		return NewValueReflect(value)
 	case nil:
 		return &Value{Args: nil, Length: 0}
+	case Subquery:
+		return &Value{sub: v.subqueryBuilder(), Length: 1}
 	case []any:
 		return &Value{Args: v, Length: len(v)}
 	case []int64:
@@ -2,7 +2,7 @@ package goent
 
 import (
 	"context"
-	"maps"
+	"fmt"
 	"reflect"
 	"slices"
 	"strings"
@@ -43,6 +43,9 @@ type Foreign struct {
 	Where      Condition   // WHERE clause for filtering
 	RefType    string      // Type name of the referenced struct (e.g. "Contributor" for AssigneeID)
 
+	ForeignKeys   []string // Foreign key columns of a key spanning several columns (nil for a single column)
+	ReferenceKeys []string // Referenced columns of a composite key, defaults to the primary key
+
 	mountFieldIdx atomic.Int32 // Cached field index for MountField (-1 = not found, 0 = not cached, >0 = index+1)
 }
 
@@ -96,20 +99,23 @@ func QueryForeign[T, R any](table *Table[T], refer *Table[R], rows []*T) error {
 	if foreign = GetForeign(table, refer); foreign == nil {
 		return nil
 	}
+	if len(foreign.ForeignKeys) > 0 {
+		return queryCompositeReflect(context.Background(), foreign, table, refer.TableInfo, rows)
+	}
 	switch foreign.Type {
 	default:
 		return nil
 	case O2O:
-		_, err := QuerySome2One(foreign, table, refer, rows)
+		_, err := QuerySome2OneBy[any](foreign, table, refer, rows)
 		return err
 	case M2O:
-		_, err := QuerySome2One(foreign, table, refer, rows)
+		_, err := QuerySome2OneBy[any](foreign, table, refer, rows)
 		return err
 	case O2M:
-		_, err := QueryOne2Many(foreign, table, refer, rows)
+		_, err := QueryOne2ManyBy[any](foreign, table, refer, rows)
 		return err
 	case M2M:
-		_, err := QueryMany2Many(foreign, table, refer, rows)
+		_, err := QueryMany2ManyBy[any](foreign, table, refer, rows)
 		return err
 	}
 }
@@ -183,6 +189,9 @@ func queryForeignReflect[T any](ctx context.Context, foreign *Foreign, table *Ta
 	if refInfo == nil {
 		return nil
 	}
+	if len(foreign.ForeignKeys) > 0 {
+		return queryCompositeReflect(ctx, foreign, table, refInfo, rows)
+	}
 	switch foreign.Type {
 	default:
 		return nil
@@ -195,26 +204,14 @@ func queryForeignReflect[T any](ctx context.Context, foreign *Foreign, table *Ta
 	}
 }
 
-// fieldInt64 returns the int64 value of a reflect.Value, dereferencing pointers.
-// Returns (0, false) for nil pointers.
-func fieldInt64(v reflect.Value) (int64, bool) {
-	if v.Kind() == reflect.Pointer {
-		if v.IsNil() {
-			return 0, false
-		}
-		v = v.Elem()
-	}
-	return v.Int(), true
-}
-
-// mapRowsByField indexes rows by an int64 field (FK or PK), returning a map of field value to row slice.
-// Pointer fields are dereferenced; nil pointers are skipped.
-func mapRowsByField[T any](rows []*T, col *Column) map[int64][]*T {
-	reg := make(map[int64][]*T, len(rows))
+// mapRowsByField indexes rows by a key field (FK or PK), returning a map of field value to row slice.
+// Pointer fields are dereferenced; nil pointers and zero keys are skipped.
+func mapRowsByField[T any](rows []*T, col *Column) map[any][]*T {
+	reg := make(map[any][]*T, len(rows))
 	for _, row := range rows {
 		valueOf := reflect.ValueOf(row).Elem()
 		fieldOf := valueOf.Field(col.FieldId)
-		if key, ok := fieldInt64(fieldOf); ok && key != 0 {
+		if key, ok := fieldKey(fieldOf); ok {
 			reg[key] = append(reg[key], row)
 		}
 	}
@@ -223,8 +220,8 @@ func mapRowsByField[T any](rows []*T, col *Column) map[int64][]*T {
 
 // mapRowsByPK indexes rows by their primary key, returning a map of PK value to row.
 // Also initializes foreign slice fields on each row.
-func mapRowsByPK[T any](rows []*T, table *Table[T], foreign *Foreign) map[int64]*T {
-	reg := make(map[int64]*T, len(rows))
+func mapRowsByPK[T any](rows []*T, table *Table[T], foreign *Foreign) map[any]*T {
+	reg := make(map[any]*T, len(rows))
 	pkCol := table.ColumnInfo(table.PrimaryKeys[0].ColumnName)
 	if pkCol == nil {
 		return reg
@@ -232,7 +229,7 @@ func mapRowsByPK[T any](rows []*T, table *Table[T], foreign *Foreign) map[int64]
 	for _, row := range rows {
 		valueOf := reflect.ValueOf(row).Elem()
 		pkField := valueOf.Field(pkCol.FieldId)
-		if id, ok := fieldInt64(pkField); ok && id != 0 {
+		if id, ok := fieldKey(pkField); ok {
 			reg[id] = row
 		}
 		if foreign != nil {
@@ -249,7 +246,7 @@ func querySome2OneReflect[T any](ctx context.Context, foreign *Foreign, table *T
 		return model.NewForeignKeyNotFoundError(foreign.ForeignKey)
 	}
 	reg := mapRowsByField(rows, col)
-	pkIds := slices.Sorted(maps.Keys(reg))
+	pkIds := sortedKeys(reg)
 	filter := And(foreign.Where, InBatch(foreign.Reference, pkIds, 500))
 
 	pkName := foreign.Reference.ColumnName
@@ -307,7 +304,7 @@ func queryOne2ManyReflect[T any](ctx context.Context, foreign *Foreign, table *T
 	reg := mapRowsByPK(rows, table, foreign)
 
 	fkName := foreign.ForeignKey // e.g. "order_id" in the child table
-	pkIds := slices.Sorted(maps.Keys(reg))
+	pkIds := sortedKeys(reg)
 
 	// Build filter: WHERE order_id IN (5557, ...) using the FK column in the child table
 	fkField := &Field{ColumnName: fkName}
@@ -337,18 +334,12 @@ func queryMany2ManyReflect[T any](ctx context.Context, foreign *Foreign, table *
 	}
 	reg := mapRowsByPK(rows, table, foreign)
 
-	middleData, err := QueryMiddleTableContext(ctx, foreign, table, rows, foreign.Middle.Left, foreign.Middle.Right)
+	middleData, err := queryMiddleKeys(ctx, foreign, table, rows, foreign.Middle.Left, foreign.Middle.Right)
 	if err != nil {
 		return err
 	}
 
-	rightIds := make([]int64, 0, len(middleData))
-	for _, pids := range middleData {
-		rightIds = append(rightIds, pids...)
-	}
-	slices.Sort(rightIds)
-	rightIds = slices.Compact(rightIds)
-
+	rightIds := middleRightKeys(middleData)
 	pkName := foreign.Reference.ColumnName
 	filter := And(foreign.Where, InBatch(foreign.Reference, rightIds, 500))
 	data, err := selectReferMap(ctx, refInfo, filter, pkName)
@@ -372,43 +363,39 @@ func queryMany2ManyReflect[T any](ctx context.Context, foreign *Foreign, table *
 
 // selectReferMap performs a SELECT query on a refer table and returns results as a map by pkName.
 // It uses reflection to scan rows into dynamically created structs.
-func selectReferMap(ctx context.Context, refInfo *TableInfo, filter Condition, pkName string) (map[int64]reflect.Value, error) {
-	builder := GetBuilder()
-	defer PutBuilder(builder)
-	builder.Type = model.SelectQuery
-	builder.SetTable(refInfo)
-	builder.core.Where = filter
-	builder.VisitFields = refInfo.GetSortedFields()
-
-	sqlQuery, args := builder.Build(false)
-	rows, err := refInfo.db.RawQueryContext(ctx, sqlQuery, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	result := make(map[int64]reflect.Value)
+func selectReferMap(ctx context.Context, refInfo *TableInfo, filter Condition, pkName string) (map[any]reflect.Value, error) {
+	result := make(map[any]reflect.Value)
 	pkCol := refInfo.ColumnInfo(pkName)
 	if pkCol == nil {
 		return result, nil
 	}
-	for rows.Next() {
-		val := reflect.New(refInfo.modelType)
-		dest := AppendDestTable(refInfo, val.Elem())
-		if err = rows.Scan(dest...); err != nil {
-			return nil, err
-		}
-		// Use fieldInt64 to safely dereference pointer fields before calling .Int()
-		pkField := val.Elem().Field(pkCol.FieldId)
-		if id, ok := fieldInt64(pkField); ok && id != 0 {
+	err := scanReferRows(ctx, refInfo, filter, func(val reflect.Value) {
+		// Use fieldKey to safely dereference pointer fields and normalize integer kinds
+		if id, ok := fieldKey(val.Elem().Field(pkCol.FieldId)); ok {
 			result[id] = val
 		}
-	}
-	return result, rows.Err()
+	})
+	return result, err
 }
 
 // selectReferRank performs a SELECT query on a refer table and returns results grouped by pkName.
-func selectReferRank(ctx context.Context, refInfo *TableInfo, filter Condition, pkName string) (map[int64][]reflect.Value, error) {
+func selectReferRank(ctx context.Context, refInfo *TableInfo, filter Condition, pkName string) (map[any][]reflect.Value, error) {
+	result := make(map[any][]reflect.Value)
+	pkCol := refInfo.ColumnInfo(pkName)
+	if pkCol == nil {
+		return result, nil
+	}
+	err := scanReferRows(ctx, refInfo, filter, func(val reflect.Value) {
+		if id, ok := fieldKey(val.Elem().Field(pkCol.FieldId)); ok {
+			result[id] = append(result[id], val)
+		}
+	})
+	return result, err
+}
+
+// scanReferRows performs a SELECT query on a refer table and calls fn with each scanned row.
+// It uses reflection to scan rows into dynamically created structs.
+func scanReferRows(ctx context.Context, refInfo *TableInfo, filter Condition, fn func(val reflect.Value)) error {
 	builder := GetBuilder()
 	defer PutBuilder(builder)
 	builder.Type = model.SelectQuery
@@ -419,34 +406,35 @@ func selectReferRank(ctx context.Context, refInfo *TableInfo, filter Condition,
 	sqlQuery, args := builder.Build(false)
 	rows, err := refInfo.db.RawQueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	result := make(map[int64][]reflect.Value)
-	pkCol := refInfo.ColumnInfo(pkName)
-	if pkCol == nil {
-		return result, nil
-	}
 	for rows.Next() {
 		val := reflect.New(refInfo.modelType)
 		dest := AppendDestTable(refInfo, val.Elem())
 		if err = rows.Scan(dest...); err != nil {
-			return nil, err
-		}
-		// Use fieldInt64 to safely dereference pointer fields before calling .Int()
-		pkField := val.Elem().Field(pkCol.FieldId)
-		if id, ok := fieldInt64(pkField); ok && id != 0 {
-			result[id] = append(result[id], val)
+			return err
 		}
+		fn(val)
 	}
-	return result, rows.Err()
+	return rows.Err()
 }
 
 // QuerySome2One queries and populates M2O/O2O relationships.
 // The rows parameter contains the records whose foreign fields should be populated.
-// It returns a map of foreign key IDs to referenced records.
+// It returns a map of foreign key IDs to referenced records, use QuerySome2OneBy for other key types.
 func QuerySome2One[T, R any](foreign *Foreign, table *Table[T], refer *Table[R], rows []*T) (map[int64]*R, error) {
+	return QuerySome2OneBy[int64](foreign, table, refer, rows)
+}
+
+// QuerySome2OneBy queries and populates M2O/O2O relationships like QuerySome2One,
+// it returns a map of foreign key values to referenced records, typed by the key type K.
+//
+// Example:
+//
+//	habitats, err := goent.QuerySome2OneBy[uuid.UUID](foreign, db.Animal, db.Habitat, animals)
+func QuerySome2OneBy[K comparable, T, R any](foreign *Foreign, table *Table[T], refer *Table[R], rows []*T) (map[K]*R, error) {
 	col := table.ColumnInfo(foreign.ForeignKey)
 	if col == nil {
 		return nil, model.NewForeignKeyNotFoundError(foreign.ForeignKey)
@@ -456,14 +444,18 @@ func QuerySome2One[T, R any](foreign *Foreign, table *Table[T], refer *Table[R],
 	}
 	reg := mapRowsByField(rows, col)
 	pkName := foreign.Reference.ColumnName
-	pkIds := slices.Sorted(maps.Keys(reg))
+	pkIds := sortedKeys(reg)
 	filter := And(foreign.Where, InBatch(foreign.Reference, pkIds, 500))
-	data, err := refer.Select().Filter(filter).Map(pkName)
+	data, err := MapBy[K](refer.Select().Filter(filter), pkName)
 	if err != nil {
 		return data, err
 	}
 	for id, matchedRows := range reg {
-		if val, ok := data[id]; ok {
+		key, ok := convertKey[K](id)
+		if !ok {
+			continue
+		}
+		if val, ok := data[key]; ok {
 			for _, row := range matchedRows {
 				elem := reflect.ValueOf(row).Elem()
 				idx := foreign.getMountFieldIdx(elem)
@@ -479,22 +471,28 @@ func QuerySome2One[T, R any](foreign *Foreign, table *Table[T], refer *Table[R],
 
 // QueryOne2Many queries and populates one-to-many relationships.
 // The rows parameter contains the records whose foreign fields should be populated.
-// It returns a map of parent IDs to slices of child records.
+// It returns a map of parent IDs to slices of child records, use QueryOne2ManyBy for other key types.
 func QueryOne2Many[T, R any](foreign *Foreign, table *Table[T], refer *Table[R], rows []*T) (map[int64][]*R, error) {
+	return QueryOne2ManyBy[int64](foreign, table, refer, rows)
+}
+
+// QueryOne2ManyBy queries and populates one-to-many relationships like QueryOne2Many,
+// it returns a map of parent keys to slices of child records, typed by the key type K.
+func QueryOne2ManyBy[K comparable, T, R any](foreign *Foreign, table *Table[T], refer *Table[R], rows []*T) (map[K][]*R, error) {
 	if len(rows) == 0 {
 		return nil, nil
 	}
 	reg := mapRowsByPK(rows, table, foreign)
 
-	pkName := foreign.Reference.ColumnName
-	pkIds := slices.Sorted(maps.Keys(reg))
-	filter := And(foreign.Where, InBatch(foreign.Reference, pkIds, 500))
-	data, err := refer.Select().Filter(filter).Rank(pkName)
+	fkName := foreign.ForeignKey // the column of the child table which holds the parent key
+	pkIds := sortedKeys(reg)
+	filter := And(foreign.Where, InBatch(refer.Field(fkName), pkIds, 500))
+	data, err := RankBy[K](refer.Select().Filter(filter), fkName)
 	if err != nil {
 		return data, err
 	}
 	for id, refRows := range data {
-		if row, ok := reg[id]; ok {
+		if row, ok := reg[keyOf(id)]; ok {
 			elem := reflect.ValueOf(row).Elem()
 			idx := foreign.getMountFieldIdx(elem)
 			field := fieldByCachedIdx(elem, idx)
@@ -509,8 +507,14 @@ func QueryOne2Many[T, R any](foreign *Foreign, table *Table[T], refer *Table[R],
 // QueryMany2Many queries and populates many-to-many relationships.
 // It uses the middle table to establish the relationship between two tables.
 // The rows parameter contains the records whose foreign fields should be populated.
-// Returns a map of left-side IDs to right-side records.
+// Returns a map of right-side IDs to right-side records, use QueryMany2ManyBy for other key types.
 func QueryMany2Many[T, R any](foreign *Foreign, table *Table[T], refer *Table[R], rows []*T) (map[int64]*R, error) {
+	return QueryMany2ManyBy[int64](foreign, table, refer, rows)
+}
+
+// QueryMany2ManyBy queries and populates many-to-many relationships like QueryMany2Many,
+// it returns a map of right-side keys to right-side records, typed by the key type K.
+func QueryMany2ManyBy[K comparable, T, R any](foreign *Foreign, table *Table[T], refer *Table[R], rows []*T) (map[K]*R, error) {
 	if foreign.Middle == nil {
 		return nil, model.ErrMiddleTableNotSet
 	}
@@ -519,21 +523,15 @@ func QueryMany2Many[T, R any](foreign *Foreign, table *Table[T], refer *Table[R]
 	}
 	reg := mapRowsByPK(rows, table, foreign)
 
-	middleData, err := QueryMiddleTable(foreign, table, rows, foreign.Middle.Left, foreign.Middle.Right)
+	middleData, err := queryMiddleKeys(context.Background(), foreign, table, rows, foreign.Middle.Left, foreign.Middle.Right)
 	if err != nil {
 		return nil, err
 	}
 
-	rightIds := make([]int64, 0, len(middleData))
-	for _, pids := range middleData {
-		rightIds = append(rightIds, pids...)
-	}
-	slices.Sort(rightIds)
-	rightIds = slices.Compact(rightIds)
-
+	rightIds := middleRightKeys(middleData)
 	pkName := foreign.Reference.ColumnName
 	filter := And(foreign.Where, InBatch(foreign.Reference, rightIds, 500))
-	data, err := refer.Select().Filter(filter).Map(pkName)
+	data, err := MapBy[K](refer.Select().Filter(filter), pkName)
 	if err != nil {
 		return data, err
 	}
@@ -548,7 +546,11 @@ func QueryMany2Many[T, R any](foreign *Foreign, table *Table[T], refer *Table[R]
 			}
 			products := reflect.MakeSlice(field.Type(), 0, len(rightIdList))
 			for _, rightId := range rightIdList {
-				if product, ok := data[rightId]; ok {
+				key, ok := convertKey[K](rightId)
+				if !ok {
+					continue
+				}
+				if product, ok := data[key]; ok {
 					products = reflect.Append(products, reflect.ValueOf(product))
 				}
 			}
@@ -566,7 +568,30 @@ func QueryMiddleTable[T any](foreign *Foreign, table *Table[T], rows []*T, leftC
 }
 
 // QueryMiddleTableContext queries the middle junction table with a specific context.
+// The keys which are not integers are skipped.
 func QueryMiddleTableContext[T any](ctx context.Context, foreign *Foreign, table *Table[T], rows []*T, leftCol, rightCol string) (map[int64][]int64, error) {
+	middleData, err := queryMiddleKeys(ctx, foreign, table, rows, leftCol, rightCol)
+	if err != nil || middleData == nil {
+		return nil, err
+	}
+	data := make(map[int64][]int64, len(middleData))
+	for left, rights := range middleData {
+		leftId, ok := convertKey[int64](left)
+		if !ok {
+			continue
+		}
+		for _, right := range rights {
+			if rightId, ok := convertKey[int64](right); ok {
+				data[leftId] = append(data[leftId], rightId)
+			}
+		}
+	}
+	return data, nil
+}
+
+// queryMiddleKeys queries the middle junction table, it returns a map of left-side keys
+// to slices of right-side keys of any type, integer keys are int64.
+func queryMiddleKeys[T any](ctx context.Context, foreign *Foreign, table *Table[T], rows []*T, leftCol, rightCol string) (map[any][]any, error) {
 	if foreign.Middle == nil {
 		return nil, model.ErrMiddleTableNotSet
 	}
@@ -576,18 +601,20 @@ func QueryMiddleTableContext[T any](ctx context.Context, foreign *Foreign, table
 		return nil, nil
 	}
 
-	pkIds := make([]int64, 0, len(rows))
+	pkIds := make([]any, 0, len(rows))
 	for _, row := range rows {
 		valueOf := reflect.ValueOf(row).Elem()
 		pkField := valueOf.Field(pkCol.FieldId)
-		if id, ok := fieldInt64(pkField); ok && id != 0 {
+		if id, ok := fieldKey(pkField); ok {
 			pkIds = append(pkIds, id)
 		}
 	}
 	if len(pkIds) == 0 {
 		return nil, nil
 	}
-	slices.Sort(pkIds)
+	slices.SortFunc(pkIds, compareKeys)
+	leftType := table.modelType.Field(pkCol.FieldId).Type
+	rightType := middleRightType(foreign)
 
 	leftField := &Field{ColumnName: leftCol}
 	filter := And(foreign.Middle.Where, In(leftField, pkIds))
@@ -609,16 +636,172 @@ func QueryMiddleTableContext[T any](ctx context.Context, foreign *Foreign, table
 	}
 	defer dbRows.Close()
 
-	data := make(map[int64][]int64, len(pkIds))
+	data := make(map[any][]any, len(pkIds))
 	for dbRows.Next() {
-		var leftId, rightId int64
-		if err = dbRows.Scan(&leftId, &rightId); err != nil {
+		leftVal, rightVal := reflect.New(leftType), reflect.New(rightType)
+		if err = dbRows.Scan(leftVal.Interface(), rightVal.Interface()); err != nil {
 			return nil, err
 		}
-		data[leftId] = append(data[leftId], rightId)
+		leftId, ok := normalizeKey(leftVal)
+		if !ok {
+			continue
+		}
+		if rightId, ok := normalizeKey(rightVal); ok {
+			data[leftId] = append(data[leftId], rightId)
+		}
 	}
 	if err := dbRows.Err(); err != nil {
 		return nil, err
 	}
 	return data, nil
 }
+
+// middleRightKeys collects the distinct right-side keys of a middle table query, sorted.
+func middleRightKeys(middleData map[any][]any) []any {
+	seen := make(map[any][]any, len(middleData))
+	for _, pids := range middleData {
+		for _, pid := range pids {
+			seen[pid] = nil
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// middleRightType returns the Go type of the referenced key of a many-to-many relation.
+// It falls back to int64 when the referenced table or column is unknown.
+func middleRightType(foreign *Foreign) reflect.Type {
+	if foreign.Reference != nil {
+		if refInfo := GetTableInfo(foreign.Reference.TableAddr); refInfo != nil {
+			if typ := pkFieldType(refInfo, foreign.Reference.ColumnName); typ != nil {
+				return typ
+			}
+		}
+	}
+	return reflect.TypeFor[int64]()
+}
+
+// queryCompositeReflect performs O2O/M2O/O2M queries for keys spanning several columns.
+// For O2O/M2O the rows hold the foreign key columns and the refer table holds the key,
+// for O2M the rows hold the key and the refer table holds the foreign key columns.
+func queryCompositeReflect[T any](ctx context.Context, foreign *Foreign, table *Table[T], refInfo *TableInfo, rows []*T) error {
+	localInfo, localCols, referCols := table.TableInfo, foreign.ForeignKeys, foreign.ReferenceKeys
+	if foreign.Type == O2M {
+		localCols, referCols = foreign.ReferenceKeys, foreign.ForeignKeys
+		if len(localCols) == 0 {
+			localCols = primaryKeyNames(localInfo)
+		}
+		for _, row := range rows {
+			initForeignSlice(row, foreign)
+		}
+	} else if len(referCols) == 0 {
+		referCols = primaryKeyNames(refInfo)
+	}
+	if foreign.Type == M2M || len(localCols) != len(referCols) {
+		return model.NewForeignKeyNotFoundError(strings.Join(foreign.ForeignKeys, "|"))
+	}
+
+	reg, tuples, err := mapRowsByColumns(localInfo, rows, localCols)
+	if err != nil || len(reg) == 0 {
+		return err
+	}
+	data := make(map[string][]reflect.Value, len(reg))
+	for chunk := range slices.Chunk(tuples, 200) {
+		filter := And(foreign.Where, tuplesCondition(refInfo, referCols, chunk))
+		err = scanReferRows(ctx, refInfo, filter, func(val reflect.Value) {
+			if key, _, ok := tupleKey(refInfo, val.Elem(), referCols); ok {
+				data[key] = append(data[key], val)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	sliceType := reflect.SliceOf(reflect.PointerTo(refInfo.modelType))
+	for key, matchedRows := range reg {
+		refRows, ok := data[key]
+		if !ok {
+			continue
+		}
+		for _, row := range matchedRows {
+			if foreign.Type != O2M {
+				setForeignField(row, foreign.MountField, refRows[0].Interface())
+				continue
+			}
+			sliceVal := reflect.MakeSlice(sliceType, len(refRows), len(refRows))
+			for i, r := range refRows {
+				sliceVal.Index(i).Set(r)
+			}
+			setForeignField(row, foreign.MountField, sliceVal.Interface())
+		}
+	}
+	return nil
+}
+
+// mapRowsByColumns indexes rows by the values of several columns.
+// It returns the rows grouped by tuple key and the distinct value tuples in row order.
+// Rows with a NULL or zero value in any of the columns are skipped.
+func mapRowsByColumns[T any](info *TableInfo, rows []*T, cols []string) (map[string][]*T, [][]any, error) {
+	for _, name := range cols {
+		if info.ColumnInfo(name) == nil {
+			return nil, nil, model.NewColumnNotFoundError(name)
+		}
+	}
+	reg := make(map[string][]*T, len(rows))
+	var tuples [][]any
+	for _, row := range rows {
+		key, values, ok := tupleKey(info, reflect.ValueOf(row).Elem(), cols)
+		if !ok {
+			continue
+		}
+		if _, seen := reg[key]; !seen {
+			tuples = append(tuples, values)
+		}
+		reg[key] = append(reg[key], row)
+	}
+	return reg, tuples, nil
+}
+
+// tupleKey returns a map key for the values of several columns of a row, and the values.
+func tupleKey(info *TableInfo, valueOf reflect.Value, cols []string) (string, []any, bool) {
+	var buf strings.Builder
+	values := make([]any, len(cols))
+	for i, name := range cols {
+		col := info.ColumnInfo(name)
+		if col == nil {
+			return "", nil, false
+		}
+		key, ok := fieldKey(valueOf.Field(col.FieldId))
+		if !ok {
+			return "", nil, false
+		}
+		values[i] = key
+		if i > 0 {
+			buf.WriteByte(0)
+		}
+		fmt.Fprint(&buf, key)
+	}
+	return buf.String(), values, true
+}
+
+// tuplesCondition builds (a = ? AND b = ?) OR (a = ? AND b = ?) ... for the given value tuples.
+func tuplesCondition(info *TableInfo, cols []string, tuples [][]any) Condition {
+	branches := make([]Condition, 0, len(tuples))
+	for _, values := range tuples {
+		conds := make([]Condition, len(cols))
+		for i, name := range cols {
+			conds[i] = Equals(&Field{TableAddr: info.TableAddr, ColumnName: name}, values[i])
+		}
+		branches = append(branches, And(conds...))
+	}
+	return Or(branches...)
+}
+
+// primaryKeyNames returns the primary key column names in struct field order.
+func primaryKeyNames(info *TableInfo) []string {
+	names := make([]string, len(info.PrimaryKeys))
+	for i, pk := range info.PrimaryKeys {
+		names[i] = pk.ColumnName
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package goent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azhai/goent/model"
+)
+
+// fullTextConfig is the text search configuration of PostgreSQL, which splits and lowercases
+// the words like the unicode61 tokenizer of SQLite FTS5, without stemming.
+const fullTextConfig = "'simple'"
+
+// fullTextWords splits a search query into its words, which all have to match.
+func fullTextWords(query string) []string {
+	return strings.Fields(query)
+}
+
+// fts5Query quotes the words of a search query for a FTS5 MATCH, where they all have to match.
+func fts5Query(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// fullTextTable returns the FTS5 table of a SQLite table with goe:"fulltext" columns,
+// and the primary key which is its rowid, or ErrFullTextKey without a single integer primary key.
+func fullTextTable(field *Field) (string, *Field, error) {
+	info := GetTableInfo(field.TableAddr)
+	if info == nil || info.GetPKField() == nil ||
+		!isIntegerKind(info.modelType.Field(info.PrimaryKeys[0].Column.FieldId).Type.Kind()) {
+		return "", nil, fmt.Errorf("%w: column %s", model.ErrFullTextKey, field.ColumnName)
+	}
+	return info.TableName + "_fts", info.GetPKField(), nil
+}
+
+// Match creates a full-text search condition on a column tagged with goe:"fulltext",
+// the rows match when the column has all the words of the query. It uses the GIN index
+// of to_tsvector on PostgreSQL and the FTS5 table of the column on SQLite, where a table
+// without a single integer primary key fails the statement with ErrFullTextKey.
+// It is not the Match methods of the statements, which filter by the non-zero fields of an object.
+//
+// Example:
+//
+//	name := db.Product.Field("name")
+//	products, err := db.Product.Select().Filter(goent.Match(name, "red shoes")).
+//	    OrderByCols(&goent.Order{Field: goent.MatchRank(name, "red shoes"), Desc: true}).All()
+func Match(field *Field, query string) Condition {
+	words := fullTextWords(query)
+	if len(words) == 0 {
+		return Condition{Template: "1 = 0"} // an empty query matches nothing
+	}
+	if field.isPostgres() {
+		return Condition{Template: "to_tsvector(" + fullTextConfig + ", %s) @@ plainto_tsquery(" + fullTextConfig + ", ?)",
+			Fields: []*Field{field}, Values: []*Value{NewValue(strings.Join(words, " "))}}
+	}
+	fts, pk, err := fullTextTable(field)
+	if err != nil {
+		return errorCondition(err)
+	}
+	return Condition{Template: "%s IN (SELECT rowid FROM " + fts + " WHERE " + fts + "." + field.ColumnName + " MATCH ?)",
+		Fields: []*Field{pk}, Values: []*Value{NewValue(fts5Query(words))}}
+}
+
+// MatchRank returns the relevance of a column to a full-text search query, as a field which
+// OrderBy sorts and Select reads. The higher the rank the better the match, a row which does
+// not match has the rank 0. It is ts_rank on PostgreSQL and the negated bm25 of FTS5 on SQLite,
+// so the ranks are only comparable within a database. On SQLite the rank of a table
+// without a single integer primary key is 0, like for an empty query, Match fails on it.
+func MatchRank(field *Field, query string) *Field {
+	words := fullTextWords(query)
+	if field.isPostgres() {
+		expr := *field
+		expr.FieldId, expr.isJSON = -1, false
+		expr.Function = "ts_rank(to_tsvector(" + fullTextConfig + ", %s), plainto_tsquery(" + fullTextConfig +
+			", " + sqlLiteral(strings.Join(words, " ")) + "))"
+		return &expr
+	}
+	fts, pk, err := fullTextTable(field)
+	if err != nil {
+		pk = field
+	}
+	expr := *pk
+	expr.FieldId, expr.isJSON = -1, false
+	if len(words) == 0 || err != nil {
+		expr.Function = "0 * %s"
+		return &expr
+	}
+	expr.Function = "COALESCE((SELECT -bm25(" + fts + ") FROM " + fts + " WHERE " + fts + "." + field.ColumnName +
+		" MATCH " + sqlLiteral(fts5Query(words)) + " AND " + fts + ".rowid = %s), 0)"
+	return &expr
+}
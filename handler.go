@@ -7,19 +7,40 @@ import (
 	"reflect"
 
 	"github.com/azhai/goent/model"
+	"github.com/azhai/goent/utils"
 )
 
-// Entity is the interface for entities that have an ID.
+// Entity is the interface for entities that have an integer ID.
 type Entity interface {
 	GetID() int64
 	SetID(int64)
 }
 
+// KeyEntity is the interface for entities whose primary key is not an integer,
+// such as a string or a UUID. It is typically implemented by code generated by goent-gen.
+type KeyEntity interface {
+	GetKey() any
+}
+
 // GenUpdatePairs is an interface for models that can generate update pairs.
-// It is typically implemented by code generated by goent-gen.
+// It is typically implemented by code generated by goent-gen,
+// together with Entity or KeyEntity depending on the primary key type.
 type GenUpdatePairs interface {
 	UpdatePairs() []Pair
-	Entity
+}
+
+// entityKey returns the primary key of an Entity or KeyEntity.
+// Returns (nil, false) if the object implements neither or its key is zero.
+func entityKey(obj any) (any, bool) {
+	switch ent := obj.(type) {
+	case Entity:
+		if id := ent.GetID(); id > 0 {
+			return id, true
+		}
+	case KeyEntity:
+		return fieldKey(reflect.ValueOf(ent.GetKey()))
+	}
+	return nil, false
 }
 
 // GenScanDest is an interface for models that can provide scan destinations.
@@ -65,7 +86,7 @@ func CreateFetchFunc(tblInfo *TableInfo, fields []*Field, foreigns []*Foreign) F
 	}
 	return func(target any) []any {
 		valueOf := reflect.ValueOf(target).Elem()
-		if len(fields) > 0 && fields[0].Function != "" {
+		if len(fields) > 0 && (fields[0].Function != "" || ctx.isDTO(valueOf.Type())) {
 			return FlattenDest(valueOf)
 		}
 		if len(fields) > 0 {
@@ -200,6 +221,7 @@ type fieldDestInfo struct {
 	isWildcard bool    // Whether this is a "*" column selector
 	isMain     bool    // Whether this field belongs to the main table
 	mountIdx   int     // Mount index for foreign fields (0 = not foreign)
+	isJSON     bool    // Whether the column is tagged with goe:"json"
 }
 
 // fetchContext holds precomputed context for fetching and scanning query results.
@@ -216,6 +238,13 @@ type fetchContext struct {
 	mainTableAddr  uintptr
 }
 
+// isDTO returns true if the rows are scanned into another struct than the table model,
+// whose fields receive the selected columns in order.
+func (ctx *fetchContext) isDTO(typ reflect.Type) bool {
+	info := ctx.tblInfo
+	return info != nil && info.modelType != nil && typ != info.modelType && len(ctx.foreigns) == 0
+}
+
 type foreignOffset struct {
 	mountIdx     int
 	elemType     reflect.Type
@@ -258,6 +287,7 @@ func (ctx *fetchContext) initDestInfos() {
 			tableAddr:  fld.TableAddr,
 			isWildcard: fld.ColumnName == "*",
 			isMain:     fld.TableAddr == ctx.mainTableAddr && fld.TableAddr != 0,
+			isJSON:     fld.isJSON,
 		}
 		for _, fo := range ctx.foreignOffsets {
 			if fo.foreignTable == fld.TableAddr && fo.foreignTable != 0 {
@@ -290,10 +320,11 @@ func (ctx *fetchContext) buildDest(valueOf reflect.Value) []any {
 			if info := GetTableInfo(di.tableAddr); info != nil {
 				dest = append(dest, AppendDestTable(info, valueOf)...)
 			}
+		} else if di.fieldId < 0 {
+			dest = append(dest, &dummy) // computed column, e.g. a window function, without a model field
 		} else if !di.isMain && di.tableAddr != 0 {
 			if fv, ok := foreignValues[di.tableAddr]; ok {
-				fieldOf := fv.Elem().Field(di.fieldId)
-				dest = append(dest, fieldOf.Addr().Interface())
+				dest = append(dest, di.dest(fv.Elem().Field(di.fieldId)))
 			} else {
 				dest = append(dest, &dummy)
 			}
@@ -302,13 +333,20 @@ func (ctx *fetchContext) buildDest(valueOf reflect.Value) []any {
 				panic(fmt.Sprintf("goent: buildDest field index out of range: fieldId=%d, numFields=%d, type=%s, tableName=%s, mainTableAddr=%d, fieldTableAddr=%d",
 					di.fieldId, valueOf.NumField(), valueOf.Type().Name(), ctx.tblInfo.TableName, ctx.mainTableAddr, di.tableAddr))
 			}
-			fieldOf := valueOf.Field(di.fieldId)
-			dest = append(dest, fieldOf.Addr().Interface())
+			dest = append(dest, di.dest(valueOf.Field(di.fieldId)))
 		}
 	}
 	return dest
 }
 
+// dest returns the scan destination of the field value.
+func (di fieldDestInfo) dest(fieldOf reflect.Value) any {
+	if di.isJSON {
+		return &jsonDest{ptr: fieldOf.Addr().Interface()}
+	}
+	return fieldOf.Addr().Interface()
+}
+
 // CreateForeignDest creates destination pointers for foreign key relationship fields.
 // It initializes the related struct field and returns pointers to its columns for scanning.
 func CreateForeignDest(valueOf reflect.Value, foreign *Foreign) []any {
@@ -337,8 +375,7 @@ func AppendDestTable(info *TableInfo, valueOf reflect.Value) []any {
 	fields := info.GetSortedFields()
 	dest := make([]any, len(fields))
 	for i, fld := range fields {
-		fieldOf := valueOf.Field(fld.FieldId)
-		dest[i] = fieldOf.Addr().Interface()
+		dest[i] = fld.dest(valueOf.Field(fld.FieldId))
 	}
 	return dest
 }
@@ -348,10 +385,19 @@ func FlattenDest(valueOf reflect.Value) []any {
 	var dest []any
 	valueType := valueOf.Type()
 	for i := range valueOf.NumField() {
-		if geoTag := valueType.Field(i).Tag.Get("goe"); geoTag == "-" {
+		geoTag := valueType.Field(i).Tag.Get("goe")
+		if geoTag == "-" {
 			continue
 		}
 		fieldOf := valueOf.Field(i)
+		if utils.HasTagValue(geoTag, "json") {
+			dest = append(dest, &jsonDest{ptr: fieldOf.Addr().Interface()})
+			continue
+		}
+		if isArrayType(fieldOf.Type()) {
+			dest = append(dest, ScanArray(fieldOf.Addr().Interface()))
+			continue
+		}
 		if fieldOf.Kind() == reflect.Slice {
 			continue
 		}
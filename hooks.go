@@ -0,0 +1,263 @@
+package goent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/azhai/goent/model"
+)
+
+// BeforeInsertHook is implemented by models that run code before they are inserted.
+// An error aborts the INSERT. Insert().One(), Insert().All(), InsertOne() and Save().One() call it.
+type BeforeInsertHook interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInsertHook is implemented by models that run code after they are inserted.
+type AfterInsertHook interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// BeforeUpdateHook is implemented by models that run code before they are updated.
+// An error aborts the UPDATE. Save().One() calls it on the saved row, Update().ByPK() and
+// Update().ByKeys() on the row loaded before the update.
+type BeforeUpdateHook interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdateHook is implemented by models that run code after they are updated.
+// Update().ByPK() and Update().ByKeys() call it on the row loaded again after the update.
+type AfterUpdateHook interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// BeforeDeleteHook is implemented by models that run code before they are deleted.
+// An error aborts the DELETE. Delete().ByPK() and Delete().ByKeys() call it on the row loaded before the delete.
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterDeleteHook is implemented by models that run code after they are deleted.
+type AfterDeleteHook interface {
+	AfterDelete(ctx context.Context) error
+}
+
+// AfterFindHook is implemented by models that run code after they are scanned from a SELECT.
+// An error stops the query and is returned instead of the row.
+type AfterFindHook interface {
+	AfterFind(ctx context.Context) error
+}
+
+// HookStatement describes an UPDATE or DELETE statement matching its rows by a condition,
+// it is passed to the statement hooks which run without a row.
+type HookStatement struct {
+	Table    string         // database table name
+	Where    string         // WHERE clause template, empty when the statement has no condition
+	Changes  map[string]any // column changes of an UPDATE, nil for a DELETE
+	Affected int64          // number of affected rows, only set for the After hooks
+}
+
+// BeforeUpdateStatementHook is implemented by models that run code before an UPDATE statement
+// without a row: Update().Exec(), UpdateByID() and Restore(). An error aborts the UPDATE.
+// It is called on a zero value of the model, the statement tells which rows are updated.
+type BeforeUpdateStatementHook interface {
+	BeforeUpdateStatement(ctx context.Context, stmt *HookStatement) error
+}
+
+// AfterUpdateStatementHook is implemented by models that run code after an UPDATE statement without a row.
+type AfterUpdateStatementHook interface {
+	AfterUpdateStatement(ctx context.Context, stmt *HookStatement) error
+}
+
+// BeforeDeleteStatementHook is implemented by models that run code before a DELETE statement
+// without a row: Delete().Exec() and DeleteByID(), also when the delete is soft. An error aborts the DELETE.
+// It is called on a zero value of the model, the statement tells which rows are deleted.
+type BeforeDeleteStatementHook interface {
+	BeforeDeleteStatement(ctx context.Context, stmt *HookStatement) error
+}
+
+// AfterDeleteStatementHook is implemented by models that run code after a DELETE statement without a row.
+type AfterDeleteStatementHook interface {
+	AfterDeleteStatement(ctx context.Context, stmt *HookStatement) error
+}
+
+// hookTxKey is the context key of the transaction passed to hooks.
+type hookTxKey struct{}
+
+// TransactionFromContext returns the transaction of the statement running a hook.
+// It returns false when the statement does not run inside a transaction.
+//
+// Example:
+//
+//	func (u *User) AfterInsert(ctx context.Context) error {
+//		if tx, ok := goent.TransactionFromContext(ctx); ok {
+//			return db.AuditLog.Insert().OnTransaction(tx).One(&AuditLog{UserID: u.ID})
+//		}
+//		return db.AuditLog.InsertContext(ctx).One(&AuditLog{UserID: u.ID})
+//	}
+func TransactionFromContext(ctx context.Context) (model.Transaction, bool) {
+	tx, ok := ctx.Value(hookTxKey{}).(model.Transaction)
+	return tx, ok
+}
+
+// hookContext returns the context passed to hooks, carrying the transaction if conn is one.
+func hookContext(ctx context.Context, conn model.Connection) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tx, ok := conn.(model.Transaction); ok {
+		return context.WithValue(ctx, hookTxKey{}, tx)
+	}
+	return ctx
+}
+
+// hookStage is a point of a statement where a hook runs.
+type hookStage uint8
+
+const (
+	hookBeforeInsert hookStage = iota
+	hookAfterInsert
+	hookBeforeUpdate
+	hookAfterUpdate
+	hookBeforeDelete
+	hookAfterDelete
+	hookAfterFind
+)
+
+// runHook calls the hook of the stage if obj implements it.
+func runHook(ctx context.Context, stage hookStage, obj any) error {
+	switch stage {
+	case hookBeforeInsert:
+		if h, ok := obj.(BeforeInsertHook); ok {
+			return h.BeforeInsert(ctx)
+		}
+	case hookAfterInsert:
+		if h, ok := obj.(AfterInsertHook); ok {
+			return h.AfterInsert(ctx)
+		}
+	case hookBeforeUpdate:
+		if h, ok := obj.(BeforeUpdateHook); ok {
+			return h.BeforeUpdate(ctx)
+		}
+	case hookAfterUpdate:
+		if h, ok := obj.(AfterUpdateHook); ok {
+			return h.AfterUpdate(ctx)
+		}
+	case hookBeforeDelete:
+		if h, ok := obj.(BeforeDeleteHook); ok {
+			return h.BeforeDelete(ctx)
+		}
+	case hookAfterDelete:
+		if h, ok := obj.(AfterDeleteHook); ok {
+			return h.AfterDelete(ctx)
+		}
+	case hookAfterFind:
+		if h, ok := obj.(AfterFindHook); ok {
+			return h.AfterFind(ctx)
+		}
+	}
+	return nil
+}
+
+// hasHook returns true if the pointer type *T implements the hook of the stage.
+// It avoids allocating a model for statements without a row when there is no hook.
+func hasHook[T any](stage hookStage) bool {
+	var obj *T
+	switch stage {
+	case hookBeforeInsert:
+		_, ok := any(obj).(BeforeInsertHook)
+		return ok
+	case hookAfterInsert:
+		_, ok := any(obj).(AfterInsertHook)
+		return ok
+	case hookBeforeUpdate:
+		_, ok := any(obj).(BeforeUpdateHook)
+		return ok
+	case hookAfterUpdate:
+		_, ok := any(obj).(AfterUpdateHook)
+		return ok
+	case hookBeforeDelete:
+		_, ok := any(obj).(BeforeDeleteHook)
+		return ok
+	case hookAfterDelete:
+		_, ok := any(obj).(AfterDeleteHook)
+		return ok
+	case hookAfterFind:
+		_, ok := any(obj).(AfterFindHook)
+		return ok
+	}
+	return false
+}
+
+// hasStatementHook returns true if the pointer type *T implements the statement hook of the stage.
+func hasStatementHook[T any](stage hookStage) (ok bool) {
+	var obj *T
+	switch stage {
+	case hookBeforeUpdate:
+		_, ok = any(obj).(BeforeUpdateStatementHook)
+	case hookAfterUpdate:
+		_, ok = any(obj).(AfterUpdateStatementHook)
+	case hookBeforeDelete:
+		_, ok = any(obj).(BeforeDeleteStatementHook)
+	case hookAfterDelete:
+		_, ok = any(obj).(AfterDeleteStatementHook)
+	}
+	return ok
+}
+
+// runStatementHook calls the statement hook of the stage on a zero value of the model,
+// for UPDATE and DELETE statements which have no row.
+func runStatementHook[T any](ctx context.Context, conn model.Connection, stage hookStage, stmt *HookStatement) error {
+	if stmt == nil || !hasStatementHook[T](stage) {
+		return nil
+	}
+	ctx, obj := hookContext(ctx, conn), any(new(T))
+	switch stage {
+	case hookBeforeUpdate:
+		return obj.(BeforeUpdateStatementHook).BeforeUpdateStatement(ctx, stmt)
+	case hookAfterUpdate:
+		return obj.(AfterUpdateStatementHook).AfterUpdateStatement(ctx, stmt)
+	case hookBeforeDelete:
+		return obj.(BeforeDeleteStatementHook).BeforeDeleteStatement(ctx, stmt)
+	case hookAfterDelete:
+		return obj.(AfterDeleteStatementHook).AfterDeleteStatement(ctx, stmt)
+	}
+	return nil
+}
+
+// hookStatement returns the statement passed to the statement hooks of the stages,
+// or nil when the model implements none of them.
+func hookStatement[T any](info *TableInfo, where Condition, changes map[*Field]any, before, after hookStage) *HookStatement {
+	if !hasStatementHook[T](before) && !hasStatementHook[T](after) {
+		return nil
+	}
+	return &HookStatement{Table: info.TableName, Where: where.Template, Changes: changesToMap(changes)}
+}
+
+// hookRow loads the row matching the key condition for the row hooks of an UPDATE or DELETE by key.
+// It runs no AfterFind hook and returns nil when no row matches.
+func (t *Table[T]) hookRow(ctx context.Context, conn model.Connection, key Condition, trashed bool) (*T, error) {
+	s := t.SelectContext(ctx).Filter(key).Take(1)
+	if trashed {
+		s.WithTrashed()
+	}
+	s.conn = conn
+	defer PutBuilder(s.builder)
+	qr := model.CreateQuery(s.builder.Build(false))
+	c, cfg := s.Prepare(t.TableInfo)
+	row, err := qr.WrapQueryRow(ctx, c, cfg)
+	if err != nil || row == nil {
+		if errors.Is(err, model.ErrNoRows) {
+			err = nil
+		}
+		return nil, err
+	}
+	target := new(T)
+	if err = row.Scan(s.getFetchFunc()(target)...); err != nil {
+		if errors.Is(err, model.ErrNoRows) {
+			err = nil
+		}
+		return nil, err
+	}
+	return target, nil
+}
@@ -1,6 +1,7 @@
 package goent
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -10,8 +11,9 @@ import (
 // StateInsert represents an INSERT query state for inserting new records into a table
 // It provides methods for inserting single and multiple records
 type StateInsert[T any] struct {
-	table       *Table[T] // The table to insert records into
-	*StateWhere           // Embedded StateWhere for query context
+	table       *Table[T]       // The table to insert records into
+	conflict    *conflictClause // ON CONFLICT clause set by OnConflict, nil for a plain INSERT
+	*StateWhere                 // Embedded StateWhere for query context
 }
 
 // One inserts a single record into the table
@@ -22,11 +24,19 @@ func (s *StateInsert[T]) One(obj *T) error {
 	s.builder.SetTable(s.table.TableInfo)
 	s.builder.ResetForSave()
 
+	hctx := hookContext(s.ctx, s.conn)
+	if err := runHook(hctx, hookBeforeInsert, obj); err != nil {
+		return err
+	}
 	valueOf := reflect.ValueOf(obj).Elem()
+	s.table.fillAutoTimes(valueOf, false)
 	primary, retFid := CollectFields(s.builder, s.table, valueOf, nil)
 	for name, val := range primary {
 		s.builder.Changes[s.table.Field(name)] = val
 	}
+	if s.conflict != nil {
+		s.prepareUpsert(s.builder.sortedChanges())
+	}
 
 	returning := s.builder.Returning
 	sql, args := s.builder.Build(true)
@@ -38,25 +48,39 @@ func (s *StateInsert[T]) One(obj *T) error {
 	conn, cfg := s.Prepare(s.table.TableInfo)
 	info := s.table.TableInfo
 	changes := changesToMap(s.builder.Changes)
+	if s.conflict != nil && retFid >= 0 && returning != "" {
+		n, err := s.upsertReturning(qr, []*T{obj}, retFid)
+		if err != nil || n == 0 { // DoNothing skipped the row
+			return err
+		}
+		publishEvent(info.db.bus, info, conn, EventTopicInsertOne, "", extractID(valueOf, retFid), changes, n)
+		return runHook(hctx, hookAfterInsert, obj)
+	}
 	if retFid >= 0 && returning != "" && s.table.db.driver.SupportsReturning() {
 		hd := NewHandler(s.ctx, conn, cfg)
 		if err := hd.ExecuteReturning(qr, valueOf, retFid); err != nil {
 			return err
 		}
 		publishEvent(info.db.bus, info, conn, EventTopicInsertOne, "", extractID(valueOf, retFid), changes, 1)
-		return nil
+		return runHook(hctx, hookAfterInsert, obj)
 	}
 	err := qr.WrapExec(s.ctx, conn, cfg)
 	if err != nil {
 		return err
 	}
-	if retFid >= 0 && returning != "" && s.table.PrimaryKeys[0].IsAutoIncr && !s.table.db.driver.SupportsReturning() {
+	if retFid >= 0 && returning != "" && s.table.PrimaryKeys[0].IsAutoIncr && !s.table.db.driver.SupportsReturning() && s.conflict == nil {
 		if err := s.getLastInsertId(valueOf, retFid); err != nil {
 			return err
 		}
 	}
-	publishEvent(info.db.bus, info, conn, EventTopicInsertOne, "", extractID(valueOf, retFid), changes, 1)
-	return nil
+	n := int64(1)
+	if s.conflict != nil {
+		if n = qr.RowsAffected; n == 0 { // DoNothing skipped the row
+			return nil
+		}
+	}
+	publishEvent(info.db.bus, info, conn, EventTopicInsertOne, "", extractID(valueOf, retFid), changes, n)
+	return runHook(hctx, hookAfterInsert, obj)
 }
 
 // extractID reads the int64 primary key value at field index fid from a reflect.Value.
@@ -139,7 +163,19 @@ func (s *StateInsert[T]) All(retPK bool, data []*T) error {
 		fld := s.table.Field(col.ColumnName)
 		s.builder.VisitFields = append(s.builder.VisitFields, fld)
 	}
+	if s.conflict != nil {
+		s.prepareUpsert(s.builder.VisitFields)
+	}
 
+	hctx := hookContext(s.ctx, s.conn)
+	for _, row := range data {
+		if row != nil {
+			if err := runHook(hctx, hookBeforeInsert, row); err != nil {
+				return err
+			}
+			s.table.fillAutoTimes(reflect.ValueOf(row).Elem(), false)
+		}
+	}
 	size := len(s.builder.VisitFields)
 	useGenInsertValues := len(data) > 0 && data[0] != nil
 	if useGenInsertValues {
@@ -154,7 +190,7 @@ func (s *StateInsert[T]) All(retPK bool, data []*T) error {
 			valueOf := reflect.ValueOf(row).Elem()
 			for i, fld := range s.builder.VisitFields {
 				if val := valueOf.Field(fld.FieldId); val.IsValid() {
-					newbie[i] = val.Interface()
+					newbie[i] = fld.arg(val.Interface())
 				}
 			}
 		}
@@ -166,6 +202,14 @@ func (s *StateInsert[T]) All(retPK bool, data []*T) error {
 	conn, cfg := s.Prepare(s.table.TableInfo)
 	info := s.table.TableInfo
 	n := int64(len(data))
+	if s.conflict != nil && pkFid >= 0 && returning != "" {
+		n, err := s.upsertReturning(qr, data, pkFid)
+		if err != nil {
+			return err
+		}
+		publishEvent(info.db.bus, info, conn, EventTopicInsertBulk, "", extractIDs(data, pkFid), nil, n)
+		return afterInsertRows(hctx, data)
+	}
 	if pkFid >= 0 && returning != "" && isAutoIncr && s.table.db.driver.SupportsReturning() {
 		valueOf := reflect.ValueOf(data)
 		hd := NewHandler(s.ctx, conn, cfg)
@@ -173,18 +217,34 @@ func (s *StateInsert[T]) All(retPK bool, data []*T) error {
 			return err
 		}
 		publishEvent(info.db.bus, info, conn, EventTopicInsertBulk, "", extractIDs(data, pkFid), nil, n)
-		return nil
+		return afterInsertRows(hctx, data)
 	}
 	err := qr.WrapExec(s.ctx, conn, cfg)
 	if err != nil {
 		return err
 	}
-	if pkFid >= 0 && returning != "" && isAutoIncr && !s.table.db.driver.SupportsReturning() {
+	if pkFid >= 0 && returning != "" && isAutoIncr && !s.table.db.driver.SupportsReturning() && s.conflict == nil {
 		if err := s.getLastInsertIds(data, pkFid); err != nil {
 			return err
 		}
 	}
 	publishEvent(info.db.bus, info, conn, EventTopicInsertBulk, "", extractIDs(data, pkFid), nil, n)
+	return afterInsertRows(hctx, data)
+}
+
+// afterInsertRows runs the AfterInsert hook of the inserted rows.
+func afterInsertRows[T any](ctx context.Context, data []*T) error {
+	if !hasHook[T](hookAfterInsert) {
+		return nil
+	}
+	for _, row := range data {
+		if row == nil {
+			continue
+		}
+		if err := runHook(ctx, hookAfterInsert, row); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -241,6 +301,7 @@ func (s *StateInsert[T]) OnTransaction(tx model.Transaction) *StateInsert[T] {
 
 type StateSave[T any] struct {
 	table       *Table[T] // The table to save records to
+	version     Condition // Optimistic lock condition of an update, for a table with a version column
 	*StateWhere           // Embedded StateWhere for query context
 }
 
@@ -248,7 +309,7 @@ func (s *StateSave[T]) getQuery(primary Dict) model.Query {
 	if len(primary) > 0 {
 		s.builder.Type = model.UpdateQuery
 		fld := &Field{TableAddr: s.table.TableAddr}
-		s.builder.core.Where = EqualsMap(fld, primary)
+		s.builder.core.Where = And(EqualsMap(fld, primary), s.version)
 	} else {
 		s.builder.Type = model.InsertQuery
 	}
@@ -260,27 +321,60 @@ func (s *StateSave[T]) getQuery(primary Dict) model.Query {
 }
 
 // One saves a record to the table, inserting if no primary key exists or updating if it does
-// It automatically handles insert/update logic based on primary key presence
+// It automatically handles insert/update logic based on primary key presence.
+// For a table with a goe:"version" column, an update only matches the row with the version of obj,
+// increments it, and returns model.ErrStaleObject when the row was changed since it was read.
 func (s *StateSave[T]) One(obj *T) error {
 	defer PutBuilder(s.builder)
 	s.builder.SetTable(s.table.TableInfo)
 	s.builder.ResetForSave()
 
 	// Fast path: use UpdatePairs for single PK update (no reflection needed)
-	if len(s.table.PrimaryKeys) == 1 && len(s.table.Ignores) == 0 {
+	if len(s.table.PrimaryKeys) == 1 && len(s.table.Ignores) == 0 && !s.table.HasVersion() {
 		if updater, ok := any(obj).(GenUpdatePairs); ok {
-			if pkID := updater.GetID(); pkID > 0 {
-				return s.table.Update().Set(updater.UpdatePairs()...).ByPK(pkID)
+			if pk, ok := entityKey(obj); ok {
+				hctx := hookContext(s.ctx, s.conn)
+				if err := runHook(hctx, hookBeforeUpdate, obj); err != nil {
+					return err
+				}
+				s.table.fillAutoTimes(reflect.ValueOf(obj).Elem(), true)
+				upd := s.table.UpdateContext(s.ctx)
+				upd.conn, upd.skipHooks = s.conn, true
+				if err := upd.Set(updater.UpdatePairs()...).ByPK(pk); err != nil {
+					return err
+				}
+				return runHook(hctx, hookAfterUpdate, obj)
 			}
 		}
 	}
 
 	valueOf := reflect.ValueOf(obj).Elem()
 	primary, retFid := CollectFields(s.builder, s.table, valueOf, s.table.Ignores)
+	isUpdate := len(primary) > 0
+	hctx, before, after := hookContext(s.ctx, s.conn), hookBeforeInsert, hookAfterInsert
+	if isUpdate {
+		before, after = hookBeforeUpdate, hookAfterUpdate
+	}
+	if hasHook[T](before) {
+		if err := runHook(hctx, before, obj); err != nil {
+			return err
+		}
+		// collect again, the hook may have changed the row
+		clear(s.builder.Changes)
+		s.builder.Returning = ""
+		primary, retFid = CollectFields(s.builder, s.table, valueOf, s.table.Ignores)
+	}
+	for _, col := range s.table.fillAutoTimes(valueOf, isUpdate) {
+		s.builder.Changes[s.table.sortedFields[col.FieldId]] = valueOf.Field(col.FieldId).Interface()
+	}
+	var current int64
+	locked := false
+	if isUpdate {
+		s.version, current, locked = s.table.lockVersion(s.builder.Changes)
+	}
 	qr := s.Take(1).getQuery(primary)
 	conn, cfg := s.Prepare(s.table.TableInfo)
 	info := s.table.TableInfo
-	isUpdate := len(primary) > 0
 	if s.builder.Returning != "" {
 		hd := NewHandler(s.ctx, conn, cfg)
 		if err := hd.ExecuteReturning(qr, valueOf, retFid); err != nil {
@@ -291,17 +385,23 @@ func (s *StateSave[T]) One(obj *T) error {
 		} else {
 			publishEvent(info.db.bus, info, conn, EventTopicInsertOne, "", extractID(valueOf, retFid), changesToMap(s.builder.Changes), 1)
 		}
-		return nil
+		return runHook(hctx, after, obj)
 	}
 	if err := qr.WrapExec(s.ctx, conn, cfg); err != nil {
 		return err
 	}
+	if locked {
+		if qr.RowsAffected == 0 {
+			return info.staleError(current)
+		}
+		info.bumpVersion(valueOf, current)
+	}
 	if isUpdate {
 		publishEvent(info.db.bus, info, conn, EventTopicUpdate, "", nil, changesToMap(s.builder.Changes), qr.RowsAffected)
 	} else {
 		publishEvent(info.db.bus, info, conn, EventTopicInsertOne, "", nil, changesToMap(s.builder.Changes), 1)
 	}
-	return nil
+	return runHook(hctx, after, obj)
 }
 
 // Map saves records from a map, inserting or updating based on primary key presence
@@ -323,6 +423,7 @@ func (s *StateSave[T]) Map(value Dict) error {
 		fld := s.table.Field(name)
 		s.builder.Changes[fld] = val
 	}
+	s.table.touchChanges(s.builder.Changes, len(primary) == 0)
 
 	qr := s.getQuery(primary)
 	conn, cfg := s.Prepare(s.table.TableInfo)
@@ -0,0 +1,174 @@
+package goent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/azhai/goent/model"
+	"github.com/azhai/goent/utils"
+)
+
+// intoField is a struct field which a result column is scanned into.
+type intoField struct {
+	index   []int // index path of the field, through embedded structs
+	isJSON  bool  // tagged with goe:"json"
+	isArray bool  // a slice of a basic type
+}
+
+// intoFieldsCache caches the fields of a DTO type by column name, one map per type.
+var intoFieldsCache sync.Map // reflect.Type -> map[string]intoField
+
+// getIntoFields returns the fields of a struct type by column name: the column of the goe tag,
+// or the snake case and the lower case of the field name. Embedded structs are flattened.
+func getIntoFields(typ reflect.Type) map[string]intoField {
+	if cached, ok := intoFieldsCache.Load(typ); ok {
+		return cached.(map[string]intoField)
+	}
+	fields := make(map[string]intoField)
+	addIntoFields(fields, typ, nil)
+	cached, _ := intoFieldsCache.LoadOrStore(typ, fields)
+	return cached.(map[string]intoField)
+}
+
+func addIntoFields(fields map[string]intoField, typ reflect.Type, parent []int) {
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		goeTag := sf.Tag.Get("goe")
+		if goeTag == "-" || !sf.IsExported() {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			addIntoFields(fields, sf.Type, index)
+			continue
+		}
+		fld := intoField{index: index, isJSON: utils.HasTagValue(goeTag, "json"), isArray: isArrayType(sf.Type)}
+		if col, ok := utils.GetTagValue(goeTag, "column"); ok && col != "" {
+			fields[col] = fld
+			continue
+		}
+		for _, name := range []string{utils.ToSnakeCase(sf.Name), strings.ToLower(sf.Name)} {
+			if _, exist := fields[name]; !exist {
+				fields[name] = fld
+			}
+		}
+	}
+}
+
+// intoPlan maps the result columns of a query to the fields of R,
+// the columns without a field are discarded and the fields without a column keep their zero value.
+type intoPlan struct {
+	fields []*intoField
+}
+
+// newIntoPlan matches the result columns to the fields of R, by exact and then case insensitive name.
+func newIntoPlan[R any](rows model.Rows) (*intoPlan, error) {
+	cr, ok := rows.(model.ColumnRows)
+	if !ok {
+		return nil, model.ErrNoColumnNames
+	}
+	columns, err := cr.Columns()
+	if err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeFor[R]()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("goent: cannot scan rows into %v, it is not a struct", typ)
+	}
+	byName := getIntoFields(typ)
+	plan := &intoPlan{fields: make([]*intoField, len(columns))}
+	for i, col := range columns {
+		if fld, ok := byName[col]; ok {
+			plan.fields[i] = &fld
+		} else if fld, ok = byName[strings.ToLower(col)]; ok {
+			plan.fields[i] = &fld
+		}
+	}
+	return plan, nil
+}
+
+// dest returns the scan destinations of a row into target.
+func (p *intoPlan) dest(target reflect.Value) []any {
+	dest := make([]any, len(p.fields))
+	for i, fld := range p.fields {
+		if fld == nil {
+			dest[i] = new(any)
+			continue
+		}
+		fieldOf := target.FieldByIndex(fld.index)
+		switch {
+		case fld.isJSON:
+			dest[i] = ScanJSON(fieldOf.Addr().Interface())
+		case fld.isArray:
+			dest[i] = ScanArray(fieldOf.Addr().Interface())
+		default:
+			dest[i] = fieldOf.Addr().Interface()
+		}
+	}
+	return dest
+}
+
+// scanInto scans all the rows into new R structs and closes the rows.
+func scanInto[R any](rows model.Rows) (res []*R, err error) {
+	defer func() {
+		if cerr := rows.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	plan, err := newIntoPlan[R](rows)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		target := new(R)
+		if err = rows.Scan(plan.dest(reflect.ValueOf(target).Elem())...); err != nil {
+			return nil, err
+		}
+		res = append(res, target)
+	}
+	return res, rows.Err()
+}
+
+// QueryInto executes a raw SQL query and scans the rows into R structs, the result columns
+// are matched to the fields by the column of the goe tag or the snake case of the field name.
+// Unknown columns are skipped and the fields without a column keep their zero value.
+//
+// Example:
+//
+//	type UserTotal struct {
+//	    Name  string
+//	    Total float64 `goe:"column:order_total"`
+//	}
+//	totals, err := goent.QueryInto[UserTotal](ctx, db,
+//	    "SELECT u.name, SUM(o.amount) AS order_total FROM users u JOIN orders o ON o.user_id = u.id GROUP BY u.name")
+func QueryInto[R any](ctx context.Context, db *DB, rawSql string, args ...any) ([]*R, error) {
+	rows, err := db.RawQueryContext(ctx, rawSql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanInto[R](rows)
+}
+
+// Into executes the select and scans the rows into R structs by column name, like QueryInto,
+// so the fields of R need not follow the order of the selected fields.
+//
+// Example:
+//
+//	type CategoryName struct {
+//	    Name     string
+//	    ParentId int
+//	}
+//	names, err := goent.Into[CategoryName](db.Category.Select("parent_id", "name"))
+func Into[R, T, S any](s *StateSelect[T, S]) ([]*R, error) {
+	qr := model.CreateQuery(s.builder.Build(false))
+	defer PutBuilder(s.builder)
+	conn, cfg := s.Prepare(s.table.TableInfo)
+	rows, err := qr.WrapQuery(s.ctx, conn, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return scanInto[R](rows)
+}
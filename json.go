@@ -0,0 +1,287 @@
+package goent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// marshalJSON marshals a value, the error names the type of the value.
+func marshalJSON(value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("goent: cannot marshal JSON value of type %T: %w", value, err)
+	}
+	return data, nil
+}
+
+// jsonArg marshals the value of a JSON column, a field tagged with goe:"json", into its query argument,
+// strings, bytes and raw messages are already JSON and a nil pointer is NULL.
+// A value which cannot be marshaled becomes an argument which fails the statement with the error.
+func jsonArg(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.RawMessage:
+		return string(v)
+	}
+	if valueOf := reflect.ValueOf(value); valueOf.Kind() == reflect.Pointer && valueOf.IsNil() {
+		return nil
+	}
+	data, err := marshalJSON(value)
+	if err != nil {
+		return errorArg{err: err}
+	}
+	return string(data)
+}
+
+// arg returns the query argument of a value written to the column of the field.
+func (f *Field) arg(value any) any {
+	if f.isJSON {
+		return jsonArg(value)
+	}
+	return value
+}
+
+// jsonDest scans a JSON column into the model field it points to.
+type jsonDest struct {
+	ptr any
+}
+
+// ScanJSON returns a scan destination which unmarshals a JSON column into ptr,
+// it is used by the generated ScanDest methods for fields tagged with goe:"json".
+func ScanJSON(ptr any) any {
+	return &jsonDest{ptr: ptr}
+}
+
+// Scan implements the sql.Scanner interface, NULL resets the field to its zero value.
+func (d *jsonDest) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		elem := reflect.ValueOf(d.ptr).Elem()
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		// the driver decoded the JSON already, e.g. pgx for jsonb
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, d.ptr)
+}
+
+// dest returns the scan destination of the field value.
+func (f *Field) dest(fieldOf reflect.Value) any {
+	if f.isJSON {
+		return &jsonDest{ptr: fieldOf.Addr().Interface()}
+	}
+	return fieldOf.Addr().Interface()
+}
+
+// isPostgres returns true if the field belongs to a table of a PostgreSQL database.
+func (f *Field) isPostgres() bool {
+	info := GetTableInfo(f.TableAddr)
+	return info != nil && info.db != nil && info.db.DriverName() == "PostgreSQL"
+}
+
+// jsonPathKeys splits a JSON path like $.a.b[0] into its keys and array indexes.
+func jsonPathKeys(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	var keys []string
+	for key := range strings.SplitSeq(path, ".") {
+		if key = strings.Trim(key, `"`); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// sqlLiteral quotes a string as a SQL literal inside a Field function,
+// where a % is doubled for fmt.
+func sqlLiteral(s string) string {
+	s = "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// JSONPath returns the value at a path of a JSON column, like $.a.b or $.items[0],
+// as a field which conditions compare and Select reads. The value is text on PostgreSQL
+// (the #>> operator) and the SQL value of json_extract on SQLite.
+//
+// Example:
+//
+//	city := goent.JSONPath(db.User.Field("profile"), "$.address.city")
+//	users, err := db.User.Filter(goent.Equals(city, "Paris")).All()
+func JSONPath(field *Field, path string) *Field {
+	expr := *field
+	expr.FieldId, expr.isJSON = -1, false
+	if field.isPostgres() {
+		expr.Function = "%s #>> " + sqlLiteral("{"+strings.Join(jsonPathKeys(path), ",")+"}")
+	} else {
+		expr.Function = "json_extract(%s, " + sqlLiteral(path) + ")"
+	}
+	return &expr
+}
+
+// JSONHasKey creates a condition that checks if the JSON object of a column has a top level key,
+// the key may hold a JSON null.
+func JSONHasKey(field *Field, key string) Condition {
+	if field.isPostgres() {
+		return Condition{Template: "(%s -> ?::text) IS NOT NULL", Fields: []*Field{field}, Values: []*Value{NewValue(key)}}
+	}
+	path := "$." + strconv.Quote(key)
+	return Condition{Template: "json_type(%s, ?) IS NOT NULL", Fields: []*Field{field}, Values: []*Value{NewValue(path)}}
+}
+
+// JSONContains creates a condition that checks if the JSON document of a column contains a value:
+// an object contains the keys of an object with values which contain theirs, an array contains
+// for each element of an array an element which contains it, and at the top level an array
+// contains a single element. It is the @> operator on PostgreSQL, on SQLite the same recursive
+// check is built with the JSON1 functions. A value which cannot be marshaled fails the statement.
+//
+// Example:
+//
+//	cond := goent.JSONContains(db.Product.Field("tags"), []string{"sale"})
+//	cond = goent.JSONContains(db.Product.Field("attrs"), map[string]any{"size": map[string]any{"eu": 42}})
+func JSONContains(field *Field, value any) Condition {
+	data, err := marshalJSON(value)
+	if err != nil {
+		return errorCondition(err)
+	}
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	_ = dec.Decode(&doc)
+	switch doc.(type) {
+	case map[string]any, []any:
+	default:
+		doc = []any{doc} // a scalar is an element of the array
+	}
+	if field.isPostgres() {
+		data, _ = json.Marshal(doc)
+		return Condition{Template: "%s @> ?::jsonb", Fields: []*Field{field}, Values: []*Value{NewValue(string(data))}}
+	}
+	c := &jsonContainment{field: field}
+	c.buf.WriteByte('(')
+	c.contains(jsonNode{doc: "%s", path: "$"}, doc)
+	c.buf.WriteByte(')')
+	return Condition{Template: c.buf.String(), Fields: c.fields, Values: c.values}
+}
+
+// jsonContainment builds the condition of JSONContains on SQLite.
+type jsonContainment struct {
+	field   *Field
+	buf     strings.Builder
+	fields  []*Field
+	values  []*Value
+	aliases int
+}
+
+// jsonNode is a JSON value in the condition of JSONContains: the value at a path of a document,
+// which is the column or the JSON text of an element, or the element of a json_each alias.
+type jsonNode struct {
+	doc   string
+	path  string
+	alias string
+}
+
+// writeDoc writes a document, %s is the column.
+func (c *jsonContainment) writeDoc(doc string) {
+	c.buf.WriteString(doc)
+	if doc == "%s" {
+		c.fields = append(c.fields, c.field)
+	}
+}
+
+// writeType writes the JSON type of a node, e.g. 'object', 'integer' or 'true'.
+func (c *jsonContainment) writeType(n jsonNode) {
+	if n.alias != "" {
+		c.buf.WriteString(n.alias + ".type")
+		return
+	}
+	c.buf.WriteString("json_type(")
+	c.writeDoc(n.doc)
+	c.buf.WriteString(", ?)")
+	c.values = append(c.values, NewValue(n.path))
+}
+
+// writeValue writes the SQL value of a scalar node.
+func (c *jsonContainment) writeValue(n jsonNode) {
+	if n.alias != "" {
+		c.buf.WriteString(n.alias + ".value")
+		return
+	}
+	c.buf.WriteString("json_extract(")
+	c.writeDoc(n.doc)
+	c.buf.WriteString(", ?)")
+	c.values = append(c.values, NewValue(n.path))
+}
+
+// contains writes the condition that a node contains a decoded JSON value.
+func (c *jsonContainment) contains(n jsonNode, want any) {
+	doc, path := n.doc, n.path
+	if n.alias != "" { // an object or array element is JSON text
+		doc, path = n.alias+".value", "$"
+	}
+	switch v := want.(type) {
+	case map[string]any:
+		c.writeType(n)
+		c.buf.WriteString(" = 'object'")
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			c.buf.WriteString(" AND ")
+			c.contains(jsonNode{doc: doc, path: path + "." + strconv.Quote(key)}, v[key])
+		}
+	case []any:
+		c.writeType(n)
+		c.buf.WriteString(" = 'array'")
+		for _, elem := range v {
+			c.aliases++
+			alias := "je" + strconv.Itoa(c.aliases)
+			c.buf.WriteString(" AND EXISTS (SELECT 1 FROM json_each(")
+			c.writeDoc(doc)
+			c.buf.WriteString(", ?) AS " + alias + " WHERE ")
+			c.values = append(c.values, NewValue(path))
+			c.contains(jsonNode{alias: alias}, elem)
+			c.buf.WriteByte(')')
+		}
+	case nil:
+		c.writeType(n)
+		c.buf.WriteString(" = 'null'")
+	case bool:
+		c.writeType(n)
+		c.buf.WriteString(" = '" + strconv.FormatBool(v) + "'")
+	case string:
+		c.writeType(n)
+		c.buf.WriteString(" = 'text' AND ")
+		c.writeValue(n)
+		c.buf.WriteString(" = ?")
+		c.values = append(c.values, NewValue(v))
+	case json.Number:
+		c.writeType(n)
+		c.buf.WriteString(" IN ('integer', 'real') AND ")
+		c.writeValue(n)
+		c.buf.WriteString(" = ?")
+		if i, err := v.Int64(); err == nil {
+			c.values = append(c.values, NewValue(i))
+		} else {
+			f, _ := v.Float64()
+			c.values = append(c.values, NewValue(f))
+		}
+	}
+}
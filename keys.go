@@ -0,0 +1,167 @@
+package goent
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/azhai/goent/model"
+)
+
+// normalizeKey returns a comparable map key for a primary or foreign key value.
+// Pointers are dereferenced and all integer kinds become int64, so that an int
+// foreign key matches an int64 primary key. Returns (nil, false) for nil pointers
+// and for values that cannot be used as map keys, such as []byte.
+func normalizeKey(v reflect.Value) (any, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		return nil, false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), true
+	}
+	if !v.Comparable() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// keyOf returns the normalized key of a single value, or nil if it cannot be a map key.
+func keyOf(value any) any {
+	key, _ := normalizeKey(reflect.ValueOf(value))
+	return key
+}
+
+// fieldKey returns the normalized key of a struct field, skipping zero values.
+// Zero keys (0, "", uuid.Nil) never reference a row, matching the old int64-only behavior.
+func fieldKey(v reflect.Value) (any, bool) {
+	key, ok := normalizeKey(v)
+	if !ok || reflect.ValueOf(key).IsZero() {
+		return nil, false
+	}
+	return key, true
+}
+
+// convertKey converts a normalized key to the key type K of a typed map.
+// Integer keys are converted between integer types, other keys must match K exactly
+// or be convertible to it (e.g. a named string type).
+func convertKey[K comparable](key any) (K, bool) {
+	if k, ok := key.(K); ok {
+		return k, true
+	}
+	var zero K
+	valueOf := reflect.ValueOf(key)
+	typeOf := reflect.TypeOf(zero)
+	if !valueOf.IsValid() || typeOf == nil || !valueOf.CanConvert(typeOf) {
+		return zero, false
+	}
+	if valueOf.Kind() == reflect.Int64 && typeOf.Kind() == reflect.String {
+		return zero, false // int64 -> string is a rune conversion, never what we want
+	}
+	return valueOf.Convert(typeOf).Interface().(K), true
+}
+
+// compareKeys orders normalized keys so that generated IN lists are stable.
+func compareKeys(a, b any) int {
+	switch x := a.(type) {
+	case int64:
+		if y, ok := b.(int64); ok {
+			return cmp.Compare(x, y)
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// sortedKeys returns the keys of a map sorted with compareKeys.
+func sortedKeys[V any](m map[any]V) []any {
+	keys := make([]any, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, compareKeys)
+	return keys
+}
+
+// int64Keys returns the keys as int64 values.
+// Returns nil if any key is not an integer, e.g. for string or UUID primary keys.
+func int64Keys(keys []any) []int64 {
+	if keys == nil {
+		return nil
+	}
+	ids := make([]int64, 0, len(keys))
+	for _, key := range keys {
+		if n, ok := keyOf(key).(int64); ok {
+			ids = append(ids, n)
+		} else {
+			return nil
+		}
+	}
+	return ids
+}
+
+// pkFieldType returns the Go type of a column of the given table.
+// Returns nil if the column does not exist.
+func pkFieldType(info *TableInfo, columnName string) reflect.Type {
+	col := info.ColumnInfo(columnName)
+	if col == nil || info.modelType == nil {
+		return nil
+	}
+	return info.modelType.Field(col.FieldId).Type
+}
+
+// columnKey reads the value of a column from a row and converts it to the key type K.
+func columnKey[K comparable](obj any, col *Column) (K, bool) {
+	key, ok := normalizeKey(reflect.ValueOf(obj).Elem().Field(col.FieldId))
+	if !ok {
+		var zero K
+		return zero, false
+	}
+	return convertKey[K](key)
+}
+
+// keysCondition builds the WHERE condition matching every primary key column of the table.
+// keys is either a single Dict keyed by column name, or one value per primary key column
+// in the order the key fields are declared in the struct. It also returns the keys as a Dict.
+func (info *TableInfo) keysCondition(keys []any) (Condition, Dict, error) {
+	size := len(info.PrimaryKeys)
+	if size == 0 {
+		return Condition{}, nil, model.ErrNoPrimaryKey
+	}
+	var data Dict
+	if len(keys) == 1 {
+		data, _ = keys[0].(Dict)
+	}
+	if data == nil {
+		if len(keys) != size {
+			return Condition{}, nil, model.ErrKeyValues
+		}
+		data = make(Dict, size)
+		for i, pk := range info.PrimaryKeys {
+			data[pk.ColumnName] = keys[i]
+		}
+	} else if len(data) != size {
+		return Condition{}, nil, model.ErrKeyValues
+	}
+	branches := make([]Condition, 0, size)
+	for _, pk := range info.PrimaryKeys {
+		value, ok := data[pk.ColumnName]
+		if !ok {
+			return Condition{}, nil, model.ErrKeyValues
+		}
+		field := &Field{TableAddr: info.TableAddr, ColumnName: pk.ColumnName, FieldId: pk.FieldId}
+		branches = append(branches, Equals(field, value))
+	}
+	return And(branches...), data, nil
+}
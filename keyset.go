@@ -0,0 +1,185 @@
+package goent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"github.com/azhai/goent/model"
+)
+
+// Keyset holds a page of keyset pagination, with the cursors of the pages around it.
+type Keyset[T, R any] struct {
+	PageSize   int `json:"pageSize"`   // Maximum number of values per page
+	PageValues int `json:"pageValues"` // Number of values on the current page
+
+	HasPreviousPage bool   `json:"hasPreviousPage"` // Whether there may be values before the page
+	PreviousCursor  string `json:"previousCursor"`  // Cursor for Before, to get the previous page
+	HasNextPage     bool   `json:"hasNextPage"`     // Whether there are values after the page
+	NextCursor      string `json:"nextCursor"`      // Cursor for After, to get the next page
+
+	Values []*R `json:"values"` // Slice of values on the current page
+}
+
+// After sets the cursor of KeysetPagination, the page starts after the row of the cursor.
+// An empty cursor is the first page.
+func (s *StateSelect[T, R]) After(cursor string) *StateSelect[T, R] {
+	s.cursor, s.cursorBefore = cursor, false
+	return s
+}
+
+// Before sets the cursor of KeysetPagination, the page ends before the row of the cursor.
+func (s *StateSelect[T, R]) Before(cursor string) *StateSelect[T, R] {
+	s.cursor, s.cursorBefore = cursor, true
+	return s
+}
+
+// KeysetPagination returns a page of at most size rows following the OrderBy columns,
+// from the cursor set by After or Before. Unlike Pagination it runs no COUNT query and
+// does not skip or repeat rows when rows are inserted meanwhile.
+// The primary key is added to the order to make it unique. The order columns must be
+// selected, not NULL, and the result type must have the model fields of these columns.
+//
+// Example:
+//
+//	page, err := db.Animal.Select().OrderBy("name").After(token).KeysetPagination(20)
+//	// next page: db.Animal.Select().OrderBy("name").After(page.NextCursor).KeysetPagination(20)
+func (s *StateSelect[T, R]) KeysetPagination(size int) (*Keyset[T, R], error) {
+	if size <= 0 {
+		size = 10
+	}
+	orders := s.keysetOrders()
+	if s.cursor != "" {
+		values, err := decodeCursor(s.table.TableInfo, orders, s.cursor)
+		if err != nil {
+			PutBuilder(s.builder)
+			return nil, err
+		}
+		s.Filter(keysetCondition(orders, values, s.cursorBefore))
+	}
+	s.builder.Orders = orders
+	if s.cursorBefore {
+		s.builder.Orders = make([]*Order, len(orders))
+		for i, ob := range orders {
+			s.builder.Orders[i] = &Order{Field: ob.Field, Desc: !ob.Desc}
+		}
+	}
+	s.builder.core.Limit, s.builder.Offset = size+1, 0
+
+	rows, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	more := len(rows) > size
+	if more {
+		rows = rows[:size]
+	}
+	p := &Keyset[T, R]{PageSize: size, PageValues: len(rows), Values: rows}
+	if s.cursorBefore {
+		slices.Reverse(rows)
+		p.HasPreviousPage, p.HasNextPage = more, true
+	} else {
+		p.HasPreviousPage, p.HasNextPage = s.cursor != "", more
+	}
+	if len(rows) == 0 {
+		return p, nil
+	}
+	if p.HasPreviousPage {
+		if p.PreviousCursor, err = encodeCursor(s.table.TableInfo, orders, rows[0]); err != nil {
+			return nil, err
+		}
+	}
+	if p.HasNextPage {
+		if p.NextCursor, err = encodeCursor(s.table.TableInfo, orders, rows[len(rows)-1]); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// keysetOrders returns the orders of the query followed by the primary key columns which are not in them.
+func (s *StateSelect[T, R]) keysetOrders() []*Order {
+	orders := slices.Clone(s.builder.Orders)
+	for _, pk := range s.table.PrimaryKeys {
+		if !slices.ContainsFunc(orders, func(ob *Order) bool { return ob.ColumnName == pk.ColumnName }) {
+			orders = append(orders, &Order{Field: s.table.Field(pk.ColumnName)})
+		}
+	}
+	return orders
+}
+
+// keysetCondition matches the rows after the values in the order, or before them:
+// (a > ?) OR (a = ? AND b > ?) ..., with < for the descending columns.
+func keysetCondition(orders []*Order, values []any, before bool) Condition {
+	branches := make([]Condition, len(orders))
+	for i, ob := range orders {
+		conds := make([]Condition, 0, i+1)
+		for j := range i {
+			conds = append(conds, Equals(orders[j].Field, values[j]))
+		}
+		if ob.Desc != before {
+			conds = append(conds, Less(ob.Field, values[i]))
+		} else {
+			conds = append(conds, Greater(ob.Field, values[i]))
+		}
+		branches[i] = And(conds...)
+	}
+	return Or(branches...)
+}
+
+// orderValues returns the values of the order columns in a row.
+func orderValues(info *TableInfo, orders []*Order, row any) ([]any, error) {
+	valueOf := reflect.Indirect(reflect.ValueOf(row))
+	values := make([]any, len(orders))
+	for i, ob := range orders {
+		col := info.ColumnInfo(ob.ColumnName)
+		if col == nil {
+			return nil, model.NewColumnNotFoundError(ob.ColumnName)
+		}
+		fieldOf := valueOf.FieldByName(col.FieldName)
+		if !fieldOf.IsValid() {
+			return nil, model.NewColumnNotFoundError(ob.ColumnName)
+		}
+		values[i] = fieldOf.Interface()
+	}
+	return values, nil
+}
+
+// encodeCursor returns the cursor of a row: its values of the order columns, in JSON and base64.
+func encodeCursor(info *TableInfo, orders []*Order, row any) (string, error) {
+	values, err := orderValues(info, orders, row)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns the values of the order columns in a cursor, with the types of the model fields.
+func decodeCursor(info *TableInfo, orders []*Order, cursor string) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, model.ErrInvalidCursor
+	}
+	var raws []json.RawMessage
+	if err = json.Unmarshal(data, &raws); err != nil || len(raws) != len(orders) {
+		return nil, model.ErrInvalidCursor
+	}
+	values := make([]any, len(orders))
+	for i, ob := range orders {
+		typeOf := pkFieldType(info, ob.ColumnName)
+		if typeOf == nil {
+			return nil, model.NewColumnNotFoundError(ob.ColumnName)
+		}
+		value := reflect.New(typeOf)
+		if err = json.Unmarshal(raws[i], value.Interface()); err != nil {
+			return nil, model.ErrInvalidCursor
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
@@ -3,6 +3,8 @@ package goent
 import (
 	"context"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/azhai/goent/model"
 	"github.com/azhai/goent/utils"
@@ -18,6 +20,24 @@ func AutoMigrateContext(ctx context.Context, ent any) error {
 	return NewSchemaOps(getDatabase(ent)).AutoMigrate(ctx, ent)
 }
 
+// PlanMigrate reports the changes AutoMigrate would make without executing them
+func PlanMigrate(ent any) (*model.MigrationPlan, error) {
+	return PlanMigrateContext(context.Background(), ent)
+}
+
+// PlanMigrateContext reports the changes AutoMigrate would make without executing them
+// The plan holds the created tables, column, index and foreign key changes and the exact SQL
+//
+// Example:
+//
+//	plan, err := goent.PlanMigrateContext(ctx, db)
+//	if err == nil && !plan.IsEmpty() {
+//		fmt.Print(plan.String(), plan.Sql)
+//	}
+func PlanMigrateContext(ctx context.Context, ent any) (*model.MigrationPlan, error) {
+	return NewSchemaOps(getDatabase(ent)).PlanMigrate(ctx, ent)
+}
+
 // fieldDesc describes a foreign key field for migration
 type fieldDesc struct {
 	info         *TableInfo
@@ -78,6 +98,10 @@ func migrateFrom(ent any, db *DB) *dbMigrator {
 			if foreign.Type == M2M || foreign.Reference == nil {
 				continue
 			}
+			if len(foreign.ForeignKeys) > 1 {
+				dm.addCompositeForeignKey(info, foreign)
+				continue
+			}
 			colName := foreign.ForeignKey
 			if colName == "" {
 				continue
@@ -116,6 +140,9 @@ func (dm *dbMigrator) typeField(tm *model.TableMigrate, tables, elem reflect.Val
 		if goeTag == "-" || skipPrimaryKey(fieldNames, fieldOf.Name, tables, fieldOf) {
 			continue
 		}
+		if len(tagColumns(goeTag, "fk")) > 1 {
+			continue // relation on a composite key, the columns are migrated on their own and the constraint in phase 2
+		}
 
 		elemField := elem.Field(fieldId)
 		migBody := body{
@@ -132,6 +159,13 @@ func (dm *dbMigrator) typeField(tm *model.TableMigrate, tables, elem reflect.Val
 			},
 		}
 
+		if utils.HasTagValue(goeTag, "json") || isArrayType(elemField.Type()) || rangeTypeName(elemField.Type()) != "" {
+			migBody.nullable = elemField.Kind() == reflect.Pointer
+			if err = migrateAtt(migBody); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		switch elemField.Kind() {
 		case reflect.Interface, reflect.Func:
 			continue
@@ -241,6 +275,45 @@ func addRelationToMigrate(tm *model.TableMigrate, fd *fieldDesc, foreign *Foreig
 	}
 }
 
+// addCompositeForeignKey adds the constraint of a relation on a composite key to the table
+// which holds its columns: this table for O2O/M2O, the referenced table for O2M.
+func (dm *dbMigrator) addCompositeForeignKey(info *TableInfo, foreign *Foreign) {
+	localInfo, targetInfo := info, tableRegistry[foreign.Reference.TableAddr]
+	if targetInfo == nil {
+		return
+	}
+	if foreign.Type == O2M {
+		localInfo, targetInfo = targetInfo, localInfo
+	}
+	tm := dm.Tables[localInfo.TableName]
+	targetCols := foreign.ReferenceKeys
+	if len(targetCols) == 0 {
+		targetCols = primaryKeyNames(targetInfo)
+	}
+	if tm == nil || len(targetCols) != len(foreign.ForeignKeys) {
+		return
+	}
+	name := "fk_" + tm.Name + "_" + strings.Join(foreign.ForeignKeys, "_")
+	if slices.ContainsFunc(tm.ForeignKeys, func(fk model.ForeignKeyMigrate) bool { return fk.Name == name }) {
+		return // declared on both sides of the relation
+	}
+	driver := dm.db.driver
+	fk := model.ForeignKeyMigrate{
+		Name:                name,
+		Columns:             foreign.ForeignKeys,
+		TargetTable:         targetInfo.TableName,
+		EscapingTargetTable: driver.KeywordHandler(targetInfo.TableName),
+	}
+	for i, col := range foreign.ForeignKeys {
+		fk.EscapingColumns = append(fk.EscapingColumns, driver.KeywordHandler(col))
+		fk.EscapingTargetColumns = append(fk.EscapingTargetColumns, driver.KeywordHandler(targetCols[i]))
+	}
+	if targetInfo.SchemaName != "" {
+		fk.TargetSchema = &targetInfo.SchemaName
+	}
+	tm.ForeignKeys = append(tm.ForeignKeys, fk)
+}
+
 // addRelationByTag adds a foreign key relation to a TableMigrate based on struct tags
 func addRelationByTag(tm *model.TableMigrate, fd *fieldDesc, migField reflect.StructField, goeTag string, driver model.Driver, fieldId int, nullable bool) {
 	pkName := fd.targetInfo.PrimaryKeys[0].ColumnName
@@ -53,10 +53,17 @@ func resolveTypeName(t reflect.Type) string {
 
 // getTagType resolves type name from a struct field, respecting the `type:` tag override
 func getTagType(field reflect.StructField) string {
-	value := getTagValue(field.Tag.Get("goe"), "type:")
+	goeTag := field.Tag.Get("goe")
+	value := getTagValue(goeTag, "type:")
 	if value != "" {
 		return strings.ReplaceAll(value, " ", "")
 	}
+	if utils.HasTagValue(goeTag, "json") {
+		return "json"
+	}
+	if name := rangeTypeName(field.Type); name != "" {
+		return name
+	}
 	return resolveTypeName(field.Type)
 }
 
@@ -398,6 +405,16 @@ func checkIndex(b body, at model.AttributeMigrate, skipUnique bool) error {
 	if utils.HasTagValue(tagValue, "index") {
 		migTable.Indexes = append(migTable.Indexes, createIndexMigrate(b, at, false))
 	}
+
+	if utils.HasTagValue(tagValue, "fulltext") {
+		name := migTable.Name + "_fts_" + strings.ToLower(migField.Name)
+		migTable.Indexes = append(migTable.Indexes, model.IndexMigrate{
+			Name:         name,
+			EscapingName: b.driver.KeywordHandler(name),
+			FullText:     true,
+			Attributes:   []model.AttributeMigrate{at},
+		})
+	}
 	return nil
 }
 
@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql"
 	"errors"
+	"strconv"
 )
 
 var (
@@ -21,6 +22,17 @@ var (
 	ErrDuplicateIndex     = errors.New("goent: struct has two or more indexes with same name but different uniqueness/function")
 	ErrForeignKeyNotFound = errors.New("goent: foreign key not found")
 	ErrMiddleTableNotSet  = errors.New("goent: middle table not configured for M2M relation")
+	ErrMigrationVersion   = errors.New("goent: duplicate or invalid migration version")
+	ErrMigrationNotFound  = errors.New("goent: applied migration is not registered")
+	ErrKeyValues          = errors.New("goent: key values do not match the primary key columns")
+	ErrNoSoftDelete       = errors.New("goent: struct does not have a soft_delete column")
+	ErrStaleObject        = errors.New("goent: row was changed or deleted by another update")
+	ErrMissingVersion     = errors.New("goent: update of a versioned row does not set the version read before")
+	ErrHavingNoGroupBy    = errors.New("goent: Having called without GroupBy")
+	ErrFullTextKey        = errors.New("goent: full-text search needs a table with a single integer primary key")
+	ErrCopyNotSupported   = errors.New("goent: driver does not support bulk copy")
+	ErrInvalidCursor      = errors.New("goent: invalid or foreign pagination cursor")
+	ErrNoColumnNames      = errors.New("goent: driver does not report the result column names")
 )
 
 // NewColumnNotFoundError creates an error indicating that the specified column was not found.
@@ -93,3 +105,43 @@ type ForeignKeyNotFoundError struct {
 func (e *ForeignKeyNotFoundError) Error() string {
 	return "goent: foreign key " + e.ForeignKey + " not found"
 }
+
+// NewMigrationError creates an error indicating that a versioned migration step failed.
+func NewMigrationError(version int64, name string, err error) error {
+	return &MigrationError{Version: version, Name: name, Err: err}
+}
+
+// MigrationError is returned when applying or reverting a versioned migration fails.
+type MigrationError struct {
+	Version int64
+	Name    string
+	Err     error
+}
+
+func (e *MigrationError) Error() string {
+	return "goent: migration " + strconv.FormatInt(e.Version, 10) + " " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// NewStaleObjectError creates an error indicating that an optimistic locked update matched no row.
+func NewStaleObjectError(tableName string, version int64) error {
+	return &StaleObjectError{TableName: tableName, Version: version}
+}
+
+// StaleObjectError is returned when an update with a version column affects no row,
+// because the row was updated or deleted since it was read. It matches ErrStaleObject with errors.Is.
+type StaleObjectError struct {
+	TableName string
+	Version   int64
+}
+
+func (e *StaleObjectError) Error() string {
+	return "goent: row of " + e.TableName + " with version " + strconv.FormatInt(e.Version, 10) + " is stale"
+}
+
+func (e *StaleObjectError) Unwrap() error {
+	return ErrStaleObject
+}
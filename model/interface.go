@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"database/sql"
+	"iter"
 )
 
 // Driver represents a database driver
@@ -102,6 +103,14 @@ type Transaction interface {
 	SavePoint() (SavePoint, error)
 }
 
+// Copier is implemented by connections and transactions which can bulk load rows
+// It is the COPY protocol on PostgreSQL and chunked prepared INSERTs on SQLite
+type Copier interface {
+	// CopyFrom streams the rows into the columns of the table and returns the number of copied rows
+	// A connection copies all the rows in one transaction, a transaction copies them in itself
+	CopyFrom(ctx context.Context, schema, table string, columns []string, rows iter.Seq[[]any]) (int64, error)
+}
+
 // SavePoint represents a savepoint in a database transaction
 // It defines the interface for savepoint operations
 
@@ -129,3 +138,15 @@ type Row interface {
 	// Scan scans the row values into the provided destinations
 	Scan(dest ...any) error
 }
+
+// ColumnRows is implemented by the Rows of a driver which reports the names of the result columns
+type ColumnRows interface {
+	Columns() ([]string, error)
+}
+
+// NativeDest is a scan destination which wraps the pointer to a model field,
+// a driver which decodes the column natively, e.g. a PostgreSQL array into a Go slice,
+// scans into the pointer returned by NativeDest instead.
+type NativeDest interface {
+	NativeDest() any
+}
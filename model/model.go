@@ -87,6 +87,7 @@ type Migrator struct {
 	Tables  map[string]*TableMigrate // Tables to migrate
 	Schemas []string                 // Schemas to use
 	Error   error                    // Migration error
+	Plan    *MigrationPlan           // If set, the changes are recorded here instead of being executed
 }
 
 // TableMigrate represents a table to be migrated with its columns, indexes, and relationships
@@ -102,6 +103,7 @@ type TableMigrate struct {
 	Attributes   []AttributeMigrate  // Columns
 	ManyToSomes  []ManyToSomeMigrate // Many-to-one/many-to-many relationships
 	OneToSomes   []OneToSomeMigrate  // One-to-one/one-to-many relationships
+	ForeignKeys  []ForeignKeyMigrate // Foreign keys spanning several columns
 }
 
 // OrderedColumn represents a column with its position for ordering
@@ -193,6 +195,7 @@ type IndexMigrate struct {
 	EscapingName string             // Escaped index name
 	Unique       bool               // Whether the index is unique
 	Func         string             // Index function (e.g., "UPPER")
+	FullText     bool               // Full-text search index, tagged with goe:"fulltext"
 	Attributes   []AttributeMigrate // Attributes included in the index
 }
 
@@ -260,6 +263,26 @@ func (m ManyToSomeMigrate) EscapingTargetTableName() string {
 	return m.EscapingTargetTable
 }
 
+// ForeignKeyMigrate represents a foreign key constraint spanning several columns
+// The columns are migrated on their own, it only adds the constraint
+type ForeignKeyMigrate struct {
+	Name                  string   // Constraint name
+	Columns               []string // Column names in this table
+	EscapingColumns       []string // Escaped column names in this table
+	TargetTable           string   // Target table name
+	EscapingTargetTable   string   // Escaped target table name
+	EscapingTargetColumns []string // Escaped target column names, in the order of Columns
+	TargetSchema          *string  // Target schema name
+}
+
+// EscapingTargetTableName returns the escaped target table name with schema if available
+func (f ForeignKeyMigrate) EscapingTargetTableName() string {
+	if f.TargetSchema != nil && *f.TargetSchema != "" {
+		return *f.TargetSchema + "." + f.EscapingTargetTable
+	}
+	return f.EscapingTargetTable
+}
+
 // DatabaseConfig contains database configuration including logging and error handling settings
 // It controls how the database driver logs queries and handles errors
 
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"strings"
+)
+
+// MigrationPlan collects the schema changes of a migration without executing them
+// Drivers fill it when [Migrator.Plan] is set, all recording methods are safe on a nil plan
+type MigrationPlan struct {
+	CreatedTables  []string           // Tables that will be created
+	AddedColumns   []ColumnChange     // Columns added to existing tables
+	AlteredColumns []ColumnChange     // Columns whose type, nullability or default changes
+	DroppedColumns []ColumnChange     // Columns removed from existing tables
+	IndexChanges   []IndexChange      // Indexes and unique constraints created or dropped
+	ForeignKeys    []ForeignKeyChange // Foreign keys added
+	RebuiltTables  []string           // Tables rebuilt by copying into a new table (SQLite)
+	Sql            string             // The exact DDL the migration would run
+}
+
+// ColumnChange describes a planned change of one column
+type ColumnChange struct {
+	Table  string // Table name, schema qualified when available
+	Column string // Column name
+	Detail string // Type or change description
+}
+
+// IndexChange describes a planned index or unique constraint change
+type IndexChange struct {
+	Table   string // Table name, schema qualified when available
+	Index   string // Index or constraint name
+	Dropped bool   // Whether the index is dropped instead of created
+}
+
+// ForeignKeyChange describes a planned foreign key constraint
+type ForeignKeyChange struct {
+	Table  string // Table name, schema qualified when available
+	Column string // Referencing column
+	Target string // Referenced table and column
+}
+
+// IsEmpty reports whether the plan contains no changes
+func (p *MigrationPlan) IsEmpty() bool {
+	return p == nil || strings.TrimSpace(p.Sql) == ""
+}
+
+// CreateTable records a table that will be created
+func (p *MigrationPlan) CreateTable(t *TableMigrate) {
+	if p != nil {
+		p.CreatedTables = append(p.CreatedTables, t.PlanName())
+	}
+}
+
+// RebuildTable records a table that will be rebuilt
+func (p *MigrationPlan) RebuildTable(t *TableMigrate) {
+	if p != nil {
+		p.RebuiltTables = append(p.RebuiltTables, t.PlanName())
+	}
+}
+
+// AddColumn records a column added to an existing table
+func (p *MigrationPlan) AddColumn(t *TableMigrate, column, dataType string) {
+	if p != nil {
+		p.AddedColumns = append(p.AddedColumns, ColumnChange{t.PlanName(), column, dataType})
+	}
+}
+
+// AlterColumn records a change of type, nullability or default of a column
+func (p *MigrationPlan) AlterColumn(t *TableMigrate, column, detail string) {
+	if p != nil {
+		p.AlteredColumns = append(p.AlteredColumns, ColumnChange{t.PlanName(), column, detail})
+	}
+}
+
+// DropColumn records a column removed from an existing table
+func (p *MigrationPlan) DropColumn(t *TableMigrate, column string) {
+	if p != nil {
+		p.DroppedColumns = append(p.DroppedColumns, ColumnChange{Table: t.PlanName(), Column: column})
+	}
+}
+
+// ChangeIndex records an index or unique constraint that is created or dropped
+func (p *MigrationPlan) ChangeIndex(t *TableMigrate, index string, dropped bool) {
+	if p != nil {
+		p.IndexChanges = append(p.IndexChanges, IndexChange{t.PlanName(), index, dropped})
+	}
+}
+
+// AddForeignKey records a foreign key constraint
+func (p *MigrationPlan) AddForeignKey(t *TableMigrate, column, target string) {
+	if p != nil {
+		p.ForeignKeys = append(p.ForeignKeys, ForeignKeyChange{t.PlanName(), column, target})
+	}
+}
+
+// String returns a human-readable diff of the plan
+// Lines start with + for additions, ~ for changes and - for removals
+func (p *MigrationPlan) String() string {
+	if p.IsEmpty() {
+		return "no changes\n"
+	}
+	var buf strings.Builder
+	line := func(mark, kind, name, detail string) {
+		buf.WriteString(mark + " " + kind + " " + name)
+		if detail != "" {
+			buf.WriteString(" " + detail)
+		}
+		buf.WriteByte('\n')
+	}
+	for _, t := range p.CreatedTables {
+		line("+", "table", t, "")
+	}
+	for _, t := range p.RebuiltTables {
+		line("~", "table", t, "(rebuild)")
+	}
+	for _, c := range p.AddedColumns {
+		line("+", "column", c.Table+"."+c.Column, c.Detail)
+	}
+	for _, c := range p.AlteredColumns {
+		line("~", "column", c.Table+"."+c.Column, c.Detail)
+	}
+	for _, c := range p.DroppedColumns {
+		line("-", "column", c.Table+"."+c.Column, c.Detail)
+	}
+	for _, idx := range p.IndexChanges {
+		mark := "+"
+		if idx.Dropped {
+			mark = "-"
+		}
+		line(mark, "index", idx.Table+"."+idx.Index, "")
+	}
+	for _, fk := range p.ForeignKeys {
+		line("+", "foreign key", fk.Table+"."+fk.Column, "-> "+fk.Target)
+	}
+	return buf.String()
+}
+
+// PlanName returns the unescaped table name, qualified with the schema when available
+func (t TableMigrate) PlanName() string {
+	if t.Schema != nil && *t.Schema != "" {
+		return *t.Schema + "." + t.Name
+	}
+	return t.Name
+}
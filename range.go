@@ -0,0 +1,209 @@
+package goent
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RangeBound is the type of the bounds of a Range.
+type RangeBound interface {
+	int32 | int64 | float64 | time.Time
+}
+
+// rangeTimeLayout is the text format of timestamp bounds, as PostgreSQL prints them.
+const rangeTimeLayout = "2006-01-02 15:04:05.999999"
+
+// Range is a range column: int4range, int8range, numrange or tsrange on PostgreSQL
+// and a JSON object on SQLite. An unbounded side has LowerInf or UpperInf set and a zero bound.
+//
+// Example:
+//
+//	type Booking struct {
+//	    Id   int
+//	    Stay goent.Range[time.Time]
+//	}
+//	booking := &Booking{Stay: goent.NewRange(checkIn, checkOut)}
+//	open := &Booking{Stay: goent.Range[time.Time]{Lower: checkIn, UpperInf: true}}
+type Range[T RangeBound] struct {
+	Lower    T      `json:"lower"`
+	Upper    T      `json:"upper"`
+	Bounds   string `json:"bounds,omitempty"`    // "[)" when empty, "[]", "(]", "()" or "empty"
+	LowerInf bool   `json:"lower_inf,omitempty"` // the range has no lower bound
+	UpperInf bool   `json:"upper_inf,omitempty"` // the range has no upper bound
+}
+
+// NewRange returns the range [lower, upper), which includes lower and excludes upper.
+func NewRange[T RangeBound](lower, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper, Bounds: "[)"}
+}
+
+// IsEmpty returns true if the range has no values.
+func (r Range[T]) IsEmpty() bool {
+	return r.Bounds == "empty"
+}
+
+// String returns the range literal of PostgreSQL, like [1,10), an unbounded side is empty, like [1,).
+func (r Range[T]) String() string {
+	if r.IsEmpty() {
+		return "empty"
+	}
+	bounds := r.Bounds
+	if len(bounds) != 2 {
+		bounds = "[)"
+	}
+	lower, upper := "", ""
+	if !r.LowerInf {
+		lower = formatRangeBound(r.Lower)
+	}
+	if !r.UpperInf {
+		upper = formatRangeBound(r.Upper)
+	}
+	return bounds[:1] + lower + "," + upper + bounds[1:]
+}
+
+// Value implements the driver.Valuer interface with the range literal.
+func (r Range[T]) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements the sql.Scanner interface, it reads the range literal of PostgreSQL
+// and the JSON object of SQLite.
+func (r *Range[T]) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		*r = Range[T]{}
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("goent: cannot scan %T into %T", src, r)
+	}
+	if strings.HasPrefix(text, "{") {
+		*r = Range[T]{}
+		return json.Unmarshal([]byte(text), r)
+	}
+	if text == "empty" {
+		*r = Range[T]{Bounds: "empty"}
+		return nil
+	}
+	lower, upper, ok := strings.Cut(strings.TrimSpace(text), ",")
+	if !ok || len(lower) == 0 || len(upper) == 0 {
+		return fmt.Errorf("goent: invalid range literal %q", text)
+	}
+	var err error
+	*r = Range[T]{Bounds: lower[:1] + upper[len(upper)-1:]}
+	if r.Lower, r.LowerInf, err = parseRangeBound[T](lower[1:]); err != nil {
+		return err
+	}
+	r.Upper, r.UpperInf, err = parseRangeBound[T](upper[:len(upper)-1])
+	return err
+}
+
+// rangeType returns the PostgreSQL type of the range column.
+func (r Range[T]) rangeType() string {
+	switch any(r.Lower).(type) {
+	case int32:
+		return "int4range"
+	case int64:
+		return "int8range"
+	case float64:
+		return "numrange"
+	}
+	return "tsrange"
+}
+
+// rangeColumn is implemented by Range, whatever the type of the bounds.
+type rangeColumn interface {
+	rangeType() string
+}
+
+// rangeTypeName returns the PostgreSQL type of a Range field, or an empty string for other types.
+func rangeTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if rc, ok := reflect.Zero(t).Interface().(rangeColumn); ok {
+		return rc.rangeType()
+	}
+	return ""
+}
+
+// formatRangeBound writes a bound of a range literal, timestamps are quoted.
+func formatRangeBound(bound any) string {
+	if t, ok := bound.(time.Time); ok {
+		return `"` + t.Format(rangeTimeLayout) + `"`
+	}
+	return fmt.Sprint(bound)
+}
+
+// parseRangeBound reads a bound of a range literal, an unbounded side is the zero value and true.
+func parseRangeBound[T RangeBound](text string) (T, bool, error) {
+	var bound T
+	text = strings.Trim(strings.TrimSpace(text), `"`)
+	if text == "" || text == "infinity" || text == "-infinity" {
+		return bound, true, nil
+	}
+	var (
+		value any
+		err   error
+	)
+	switch any(bound).(type) {
+	case int32:
+		var n int64
+		n, err = strconv.ParseInt(text, 10, 32)
+		value = int32(n)
+	case int64:
+		value, err = strconv.ParseInt(text, 10, 64)
+	case float64:
+		value, err = strconv.ParseFloat(text, 64)
+	case time.Time:
+		value, err = time.Parse(rangeTimeLayout, text)
+	}
+	if err != nil {
+		return bound, false, fmt.Errorf("goent: invalid range bound %q: %w", text, err)
+	}
+	return value.(T), false, nil
+}
+
+// RangeContains creates a condition that checks if the range column contains a value,
+// the @> operator on PostgreSQL, on SQLite it compares the value with the bounds of the JSON object,
+// an unbounded side contains every value and an empty range none.
+//
+// Example:
+//
+//	cond := goent.RangeContains(db.Booking.Field("stay"), time.Now())
+func RangeContains(field *Field, value any) Condition {
+	if field.isPostgres() {
+		cast := ""
+		switch value.(type) {
+		case int32:
+			cast = "::integer"
+		case int, int64:
+			cast = "::bigint"
+		case float64:
+			cast = "::numeric"
+		case time.Time:
+			cast = "::timestamp"
+		}
+		return Condition{Template: "%s @> ?" + cast, Fields: []*Field{field}, Values: []*Value{NewValue(value)}}
+	}
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339Nano) // as encoding/json writes the bounds
+	}
+	fields := []*Field{field, field, field, field, field, field, field, field, field}
+	values := []*Value{NewValue(value), NewValue(value), NewValue(value), NewValue(value)}
+	return Condition{Template: "(coalesce(json_extract(%s, '$.bounds'), '') <> 'empty'" +
+		" AND (coalesce(json_extract(%s, '$.lower_inf'), 0) OR json_extract(%s, '$.lower') < ?" +
+		" OR (json_extract(%s, '$.lower') = ? AND substr(coalesce(json_extract(%s, '$.bounds'), '[)'), 1, 1) <> '('))" +
+		" AND (coalesce(json_extract(%s, '$.upper_inf'), 0) OR json_extract(%s, '$.upper') > ?" +
+		" OR (json_extract(%s, '$.upper') = ? AND substr(json_extract(%s, '$.bounds'), 2, 1) = ']')))",
+		Fields: fields, Values: values}
+}
@@ -71,3 +71,16 @@ func (s *SchemaOps) AutoMigrate(ctx context.Context, ent any) error {
 	}
 	return s.DB.driver.MigrateContext(ctx, mig.Migrator)
 }
+
+func (s *SchemaOps) PlanMigrate(ctx context.Context, ent any) (*model.MigrationPlan, error) {
+	db := getDatabase(ent)
+	mig := migrateFrom(ent, db)
+	if mig.Error != nil {
+		return nil, mig.Error
+	}
+	mig.Plan = new(model.MigrationPlan)
+	if err := s.DB.driver.MigrateContext(ctx, mig.Migrator); err != nil {
+		return nil, err
+	}
+	return mig.Plan, nil
+}
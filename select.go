@@ -17,6 +17,8 @@ type StateSelect[T, R any] struct {
 	table        *Table[T] // The table to query from
 	sameModel    bool      // Whether the result type is the same as the table model
 	withForeigns []string  // Names of related tables to eager-load after All()
+	cursor       string    // Cursor of KeysetPagination, see After and Before
+	cursorBefore bool      // The page is before the cursor instead of after it
 	*StateWhere            // Embedded StateWhere for WHERE clause construction
 }
 
@@ -42,6 +44,27 @@ func NewStateSelectFrom[T, R any](state *StateWhere, table *Table[T]) *StateSele
 	return &StateSelect[T, R]{table: table, StateWhere: state}
 }
 
+// SelectAs creates a query of the fields of the table whose rows are scanned into R,
+// a ResultFunc or a struct whose fields receive the selected columns in order.
+//
+// Example:
+//
+//	rows, err := goent.SelectAs[struct {
+//		Name string
+//		Rank int64
+//	}](db.Animal, "name", goent.RowNumber().Over(goent.OrderedBy(db.Animal.Field("age")))).All()
+func SelectAs[R, T any](table *Table[T], fields ...any) *StateSelect[T, R] {
+	return SelectAsContext[R](context.Background(), table, fields...)
+}
+
+// SelectAsContext creates a query like SelectAs with a specific context.
+func SelectAsContext[R, T any](ctx context.Context, table *Table[T], fields ...any) *StateSelect[T, R] {
+	s := NewStateSelect[T, R](ctx, table)
+	s.builder.VisitFields = make([]*Field, 0, len(fields))
+	s.builder.visitFieldsShared = false
+	return s.Select(fields...)
+}
+
 // CopyFrom copies the query builder state from another StateWhere and connection
 // It copies joins, conditions, orders, groups, limits, and offset
 func (s *StateSelect[T, R]) CopyFrom(other *StateWhere) *StateSelect[T, R] {
@@ -55,13 +78,17 @@ func (s *StateSelect[T, R]) CopyFrom(other *StateWhere) *StateSelect[T, R] {
 	s.builder.core.Limit = ob.core.Limit
 	s.builder.Offset = ob.Offset
 	s.builder.RollUp = ob.RollUp
+	s.builder.trashed = ob.trashed
+	s.builder.ctes = ob.ctes
+	s.builder.fromCTE = ob.fromCTE
+	s.builder.compounds = ob.compounds
 	// copy connection/transaction
 	s.conn = other.conn
 	return s
 }
 
 // Select specifies the fields to select from the table
-// It accepts field names as strings or Field objects
+// It accepts field names as strings, Field objects or typed Col references
 func (s *StateSelect[T, R]) Select(fields ...any) *StateSelect[T, R] {
 	// Clone VisitFields if shared with table's sortedFields
 	if s.builder.visitFieldsShared {
@@ -71,18 +98,40 @@ func (s *StateSelect[T, R]) Select(fields ...any) *StateSelect[T, R] {
 		s.builder.VisitFields = clone
 		s.builder.visitFieldsShared = false
 	}
-	var fld *Field
 	for _, one := range fields {
-		if col, ok := one.(string); ok {
-			fld = s.table.Field(col)
-		} else if fld, ok = one.(*Field); !ok {
-			continue
+		if fld, ok := s.table.resolveField(one); ok {
+			s.builder.VisitFields = append(s.builder.VisitFields, fld)
 		}
-		s.builder.VisitFields = append(s.builder.VisitFields, fld)
 	}
 	return s
 }
 
+// WithTrashed includes the soft deleted rows in the results.
+// It has no effect on tables without a goe:"soft_delete" column.
+//
+// Example:
+//
+//	users, err := db.User.Select().WithTrashed().All()
+func (s *StateSelect[T, R]) WithTrashed() *StateSelect[T, R] {
+	s.builder.trashed = trashedInclude
+	return s
+}
+
+// Unscoped removes the automatic soft delete filter, same as WithTrashed.
+func (s *StateSelect[T, R]) Unscoped() *StateSelect[T, R] {
+	return s.WithTrashed()
+}
+
+// OnlyTrashed returns only the soft deleted rows.
+//
+// Example:
+//
+//	deleted, err := db.User.Select().OnlyTrashed().All()
+func (s *StateSelect[T, R]) OnlyTrashed() *StateSelect[T, R] {
+	s.builder.trashed = trashedOnly
+	return s
+}
+
 // getFetchFunc returns a FetchFunc for the query
 // It uses generated ScanDest if available, otherwise creates a reflection-based fetcher
 func (s *StateSelect[T, R]) getFetchFunc() FetchFunc {
@@ -122,6 +171,9 @@ func (s *StateSelect[T, R]) FetchRow(qr model.Query, to FetchFunc) (*R, error) {
 	if err = row.Scan(to(target)...); err != nil {
 		return nil, err
 	}
+	if err = runHook(hookContext(s.ctx, conn), hookAfterFind, target); err != nil {
+		return nil, err
+	}
 	return target, err
 }
 
@@ -144,9 +196,17 @@ func (s *StateSelect[T, R]) One() (obj *R, err error) {
 
 // ByPK selects a single row by primary key using cached SQL.
 // This is an optimized path that bypasses query building for simple primary key lookups.
-// Only works for tables with a single primary key column.
+// Only works for tables with a single primary key column, the key can be of any type.
 // For maximum performance, use Table.FindByPK() instead which also avoids Builder allocation.
-func (s *StateSelect[T, R]) ByPK(id int64) (*R, error) {
+func (s *StateSelect[T, R]) ByPK(id any) (*R, error) {
+	if s.builder.trashed != trashedExclude && s.table.HasSoftDelete() {
+		pkField := s.table.GetPKField()
+		if pkField == nil {
+			PutBuilder(s.builder)
+			return nil, model.ErrNoPrimaryKey
+		}
+		return s.Filter(Equals(pkField, id)).One()
+	}
 	defer PutBuilder(s.builder)
 	sql := s.table.GetSelectByPKSql()
 	if sql == "" {
@@ -156,6 +216,18 @@ func (s *StateSelect[T, R]) ByPK(id int64) (*R, error) {
 	return s.FetchRow(qr, nil)
 }
 
+// ByKeys selects a single row by its full primary key, which may span several columns.
+// The keys are either one value per primary key column in struct field order,
+// or a single Dict keyed by column name.
+func (s *StateSelect[T, R]) ByKeys(keys ...any) (*R, error) {
+	cond, _, err := s.table.keysCondition(keys)
+	if err != nil {
+		PutBuilder(s.builder)
+		return nil, err
+	}
+	return s.Filter(cond).One()
+}
+
 // IterRows returns an iterator over the query results
 // It yields each row along with any error encountered
 func (s *StateSelect[T, R]) IterRows(to FetchFunc) iter.Seq2[*R, error] {
@@ -167,9 +239,17 @@ func (s *StateSelect[T, R]) IterRows(to FetchFunc) iter.Seq2[*R, error] {
 	conn, cfg := s.Prepare(s.table.TableInfo)
 	hd := NewHandler(s.ctx, conn, cfg)
 	seq := FetchResult[R](hd, qr, to)
+	afterFind := hasHook[R](hookAfterFind)
 	return func(yield func(*R, error) bool) {
 		defer PutBuilder(builder)
+		hctx := hookContext(s.ctx, conn)
 		for obj, err := range seq {
+			if afterFind && err == nil && obj != nil {
+				if err = runHook(hctx, hookAfterFind, obj); err != nil {
+					yield(nil, err)
+					return
+				}
+			}
 			if !yield(obj, err) {
 				return
 			}
@@ -198,39 +278,55 @@ func (s *StateSelect[T, R]) All() (res []*R, err error) {
 }
 
 // Map executes the query and returns results as a map keyed by the specified column
-// The key must be an integer column that exists in the table
+// The key must be an integer column that exists in the table, use MapBy for other key types
 func (s *StateSelect[T, R]) Map(key string) (map[int64]*R, error) {
+	return MapBy[int64](s, key)
+}
+
+// Rank executes the query and returns results as a map keyed by the specified column
+// Each key maps to a slice of results with that key value, use RankBy for non-integer keys
+func (s *StateSelect[T, R]) Rank(key string) (map[int64][]*R, error) {
+	return RankBy[int64](s, key)
+}
+
+// MapBy executes the query and returns results as a typed map keyed by the specified column
+// Rows whose key is NULL or cannot be converted to K are skipped
+//
+// Example:
+//
+//	foods, err := goent.MapBy[uuid.UUID](db.Food.Select(), "id")
+func MapBy[K comparable, T, R any](s *StateSelect[T, R], key string) (map[K]*R, error) {
 	var col *Column
 	if col = s.table.ColumnInfo(key); col == nil {
 		return nil, model.NewColumnNotFoundError(key)
 	}
 	size := max(s.builder.core.Limit, 0)
-	res := make(map[int64]*R, size)
+	res := make(map[K]*R, size)
 	for obj, err := range s.IterRows(nil) {
 		if err != nil {
 			return nil, err
 		}
-		if id, ok := col.GetInt64(obj); ok {
+		if id, ok := columnKey[K](obj, col); ok {
 			res[id] = obj
 		}
 	}
 	return res, nil
 }
 
-// Rank executes the query and returns results as a map keyed by the specified column
+// RankBy executes the query and returns results grouped in a typed map by the specified column
 // Each key maps to a slice of results with that key value
-func (s *StateSelect[T, R]) Rank(key string) (map[int64][]*R, error) {
+func RankBy[K comparable, T, R any](s *StateSelect[T, R], key string) (map[K][]*R, error) {
 	var col *Column
 	if col = s.table.ColumnInfo(key); col == nil {
 		return nil, model.NewColumnNotFoundError(key)
 	}
 	size := max(s.builder.core.Limit, 0)
-	res := make(map[int64][]*R, size)
+	res := make(map[K][]*R, size)
 	for obj, err := range s.IterRows(nil) {
 		if err != nil {
 			return nil, err
 		}
-		if id, ok := col.GetInt64(obj); ok {
+		if id, ok := columnKey[K](obj, col); ok {
 			res[id] = append(res[id], obj)
 		}
 	}
@@ -238,23 +334,85 @@ func (s *StateSelect[T, R]) Rank(key string) (map[int64][]*R, error) {
 }
 
 // RollUP enables rollup for aggregation queries
-// It adds ROLL UP clause to GROUP BY operations
+// It adds ROLL UP clause to GROUP BY operations: besides the rows of each group, it returns
+// subtotal rows for each prefix of the groups and a grand total row, with NULL in the rolled up columns.
+// SQLite has no ROLLUP, the subtotals are selected with UNION ALL instead.
 //
 // Example:
 //
 //	results, _ := db.Order.Select("status", "total").GroupBy("status").RollUP().All()
 func (s *StateSelect[T, R]) RollUP() *StateSelect[T, R] {
+	s.builder.RollUp = rollUpGroups
+	if s.table.db != nil && s.table.db.DriverName() == "SQLite" {
+		s.builder.RollUp = rollUpUnion
+	}
 	return s
 }
 
-// OnTransaction sets a transaction for the select query and enables FOR UPDATE lock
-// It ensures the query runs within the specified transaction
+// OnTransaction sets a transaction for the select query
+// It ensures the query runs within the specified transaction, without locking the rows,
+// see LockForUpdate and LockForShare
 func (s *StateSelect[T, R]) OnTransaction(tx model.Transaction) *StateSelect[T, R] {
-	s.builder.ForUpdate = true
 	s.StateWhere.conn = tx
 	return s
 }
 
+// locksRows returns false for databases without row locks, SQLite locks the whole database
+// when a transaction writes, so the locking clauses are left out.
+func (s *StateSelect[T, R]) locksRows() bool {
+	return s.table.db == nil || s.table.db.DriverName() != "SQLite"
+}
+
+// LockForUpdate locks the selected rows until the end of the transaction, with FOR UPDATE.
+// Other transactions wait to update, delete or lock them. In a join, only the rows of the
+// given tables are locked (FOR UPDATE OF), the rows of all the tables without tables.
+// It has no effect on SQLite, where the transaction which writes first locks the whole database.
+//
+// Example:
+//
+//	err = db.BeginTransaction(func(tx model.Transaction) error {
+//		jobs, err := db.Job.Select().OnTransaction(tx).
+//			Filter(goent.Equals(db.Job.Field("status"), "pending")).
+//			OrderBy("id").Take(10).LockForUpdate().SkipLocked().All()
+//		...
+//	})
+func (s *StateSelect[T, R]) LockForUpdate(tables ...*TableInfo) *StateSelect[T, R] {
+	if s.locksRows() {
+		s.builder.ForUpdate, s.builder.ForShare = true, false
+		s.builder.SetLockTables(tables...)
+	}
+	return s
+}
+
+// LockForShare locks the selected rows against updates and deletes until the end of the
+// transaction, with FOR SHARE. Other transactions can still read and share lock them.
+// It has no effect on SQLite.
+func (s *StateSelect[T, R]) LockForShare(tables ...*TableInfo) *StateSelect[T, R] {
+	if s.locksRows() {
+		s.builder.ForUpdate, s.builder.ForShare = false, true
+		s.builder.SetLockTables(tables...)
+	}
+	return s
+}
+
+// SkipLocked skips the rows locked by other transactions instead of waiting for them,
+// e.g. for consumers of a job queue. It must follow LockForUpdate or LockForShare.
+func (s *StateSelect[T, R]) SkipLocked() *StateSelect[T, R] {
+	if s.locksRows() {
+		s.builder.SetLockWait("SKIP LOCKED")
+	}
+	return s
+}
+
+// NoWait fails the query when a row is locked by another transaction instead of waiting.
+// It must follow LockForUpdate or LockForShare.
+func (s *StateSelect[T, R]) NoWait() *StateSelect[T, R] {
+	if s.locksRows() {
+		s.builder.SetLockWait("NOWAIT")
+	}
+	return s
+}
+
 // Filter adds filter conditions to the select query
 // It appends the specified conditions to the WHERE clause
 func (s *StateSelect[T, R]) Filter(args ...Condition) *StateSelect[T, R] {
@@ -295,6 +453,29 @@ func (s *StateSelect[T, R]) OrderBy(args ...string) *StateSelect[T, R] {
 	return s
 }
 
+// OrderByCols adds ORDER BY clauses to the query like OrderBy,
+// it accepts typed Col references, their Desc() orders, Field objects and field names
+//
+// Example:
+//
+//	c := models.BookCols(db.Book)
+//	books, err := db.Book.Select().OrderByCols(c.Genre, c.Quantity.Desc()).All()
+func (s *StateSelect[T, R]) OrderByCols(args ...any) *StateSelect[T, R] {
+	for _, one := range args {
+		switch arg := one.(type) {
+		case string:
+			s.OrderBy(arg)
+		case *Order:
+			s.builder.Orders = append(s.builder.Orders, arg)
+		default:
+			if fld, ok := s.table.resolveField(arg); ok {
+				s.builder.Orders = append(s.builder.Orders, &Order{Field: fld})
+			}
+		}
+	}
+	return s
+}
+
 // GroupBy adds GROUP BY clauses to the query
 // It groups results by the specified fields
 func (s *StateSelect[T, R]) GroupBy(args ...string) *StateSelect[T, R] {
@@ -305,6 +486,39 @@ func (s *StateSelect[T, R]) GroupBy(args ...string) *StateSelect[T, R] {
 	return s
 }
 
+// GroupByCols adds GROUP BY clauses to the query like GroupBy,
+// it accepts the fields like Select: typed Col references, Field objects and field names
+func (s *StateSelect[T, R]) GroupByCols(args ...any) *StateSelect[T, R] {
+	for _, arg := range args {
+		if fld, ok := s.table.resolveField(arg); ok {
+			grp := &Group{Field: fld, Having: Condition{}}
+			s.builder.Groups = append(s.builder.Groups, grp)
+		}
+	}
+	return s
+}
+
+// Having filters the groups with conditions on aggregates, it must follow GroupBy,
+// otherwise the query fails with ErrHavingNoGroupBy. The conditions of several calls
+// are combined with AND.
+//
+// Example:
+//
+//	total := db.Order.Field("amount").Func("SUM(%s)", "")
+//	rows, err := goent.SelectAs[struct {
+//		CustomerId int
+//		Total      float64
+//	}](db.Order, "customer_id", total).GroupBy("customer_id").
+//		Having(goent.GreaterEquals(total, 1000)).All()
+func (s *StateSelect[T, R]) Having(conds ...Condition) *StateSelect[T, R] {
+	if len(s.builder.Groups) == 0 {
+		return s.Filter(errorCondition(model.ErrHavingNoGroupBy))
+	}
+	grp := s.builder.Groups[len(s.builder.Groups)-1]
+	grp.Having = And(append([]Condition{grp.Having}, conds...)...)
+	return s
+}
+
 // Take limits the number of rows returned by the query
 // It sets the LIMIT clause to the specified value
 func (s *StateSelect[T, R]) Take(i int) *StateSelect[T, R] {
@@ -470,6 +684,23 @@ func findTableInfoByName(name string) *TableInfo {
 	return nil
 }
 
+// countRows counts the rows of the query without its ORDER BY, LIMIT and OFFSET.
+func (s *StateSelect[T, R]) countRows() (int64, error) {
+	if len(s.builder.compounds) > 0 {
+		return s.countCompound()
+	}
+	fld := &Field{TableAddr: s.table.TableAddr, ColumnName: "*", Function: "COUNT(%s)"}
+	counter := NewStateSelect[T, ResultLong](s.ctx, s.table)
+	counter.builder.VisitFields = nil
+	counter.Select(fld)
+	counter.CopyFrom(s.StateWhere)
+	counter.builder.Orders = nil
+	counter.builder.core.Limit = 0
+	counter.builder.Offset = 0
+	counter.builder.compounds = nil
+	return FetchSingleResult(counter)
+}
+
 // Pagination holds paginated query results with metadata
 // It provides information about total values, pages, and current page details
 type Pagination[T, R any] struct {
@@ -501,15 +732,7 @@ func (s *StateSelect[T, R]) Pagination(page, size int) (*Pagination[T, R], error
 		page = 1
 	}
 
-	fld := &Field{TableAddr: s.table.TableAddr, ColumnName: "*", Function: "COUNT(%s)"}
-	counter := NewStateSelect[T, ResultLong](s.ctx, s.table)
-	counter.builder.VisitFields = nil
-	counter.Select(fld)
-	counter.CopyFrom(s.StateWhere)
-	counter.builder.Orders = nil
-	counter.builder.core.Limit = 0
-	counter.builder.Offset = 0
-	count, err := FetchSingleResult(counter)
+	count, err := s.countRows()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,142 @@
+package goent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/azhai/goent/model"
+)
+
+// trashedMode decides which rows of a soft delete table a SELECT returns.
+type trashedMode uint8
+
+const (
+	trashedExclude trashedMode = iota // only rows that are not deleted (default)
+	trashedInclude                    // deleted and not deleted rows
+	trashedOnly                       // only deleted rows
+)
+
+// softDeleteKind is the kind of value stored in a soft delete column.
+type softDeleteKind uint8
+
+const (
+	softDeleteNull softDeleteKind = iota + 1 // nullable column, e.g. *time.Time, NULL means not deleted
+	softDeleteBool                           // bool flag, false means not deleted
+	softDeleteUnix                           // integer flag or unix timestamp, 0 means not deleted
+)
+
+// setSoftDelete marks the column tagged with goe:"soft_delete" as the soft delete column.
+// Supported types are nullable columns (*time.Time), bool and integers,
+// the tag is ignored on other types because they have no "not deleted" value.
+func (info *TableInfo) setSoftDelete(column *Column, typeOf reflect.Type) {
+	var kind softDeleteKind
+	switch typeOf.Kind() {
+	case reflect.Pointer:
+		kind = softDeleteNull
+	case reflect.Bool:
+		kind = softDeleteBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		kind = softDeleteUnix
+	default:
+		return
+	}
+	info.softDelete, info.softDeleteKind = column, kind
+}
+
+// HasSoftDelete returns true if the table has a column tagged with goe:"soft_delete".
+func (info *TableInfo) HasSoftDelete() bool {
+	return info.softDelete != nil
+}
+
+// softDeleteTemplate returns the condition template matching the rows of the given mode,
+// or an empty string when the table has no soft delete column or all rows are wanted.
+func (info *TableInfo) softDeleteTemplate(mode trashedMode) string {
+	if info.softDelete == nil || mode == trashedInclude {
+		return ""
+	}
+	deleted := mode == trashedOnly
+	switch info.softDeleteKind {
+	case softDeleteBool:
+		if deleted {
+			return "%s = TRUE"
+		}
+		return "%s = FALSE"
+	case softDeleteUnix:
+		if deleted {
+			return "%s <> 0"
+		}
+		return "%s = 0"
+	}
+	if deleted {
+		return "%s IS NOT NULL"
+	}
+	return "%s IS NULL"
+}
+
+// softDeleteScope returns the condition added to queries of the table for the given mode.
+func (info *TableInfo) softDeleteScope(mode trashedMode) Condition {
+	template := info.softDeleteTemplate(mode)
+	if template == "" {
+		return Condition{}
+	}
+	field := &Field{TableAddr: info.TableAddr, ColumnName: info.softDelete.ColumnName, FieldId: info.softDelete.FieldId}
+	return Condition{Template: template, Fields: []*Field{field}}
+}
+
+// softDeleteValue returns the value written to the soft delete column,
+// when deleting (deleted is true) or restoring a row.
+func (info *TableInfo) softDeleteValue(deleted bool) any {
+	switch info.softDeleteKind {
+	case softDeleteBool:
+		return deleted
+	case softDeleteUnix:
+		if deleted {
+			return time.Now().Unix()
+		}
+		return 0
+	}
+	if deleted {
+		return time.Now()
+	}
+	return nil
+}
+
+// softDeleteRows marks the rows matching where as deleted instead of removing them.
+// Rows which are already deleted keep their original value. It returns the number of marked rows.
+func softDeleteRows[T any](ctx context.Context, conn model.Connection, table *Table[T],
+	where Condition, topic string, keys []any, skipEvent bool) (int64, error) {
+	info := table.TableInfo
+	s := table.UpdateContext(ctx)
+	s.conn = conn
+	s.builder.Changes[table.Field(info.softDelete.ColumnName)] = info.softDeleteValue(true)
+	s.builder.core.Where = And(where, info.softDeleteScope(trashedExclude))
+	s.skipEvent = skipEvent || where.IsEmpty()
+	s.eventTopic, s.eventKeys = topic, keys
+	return s.exec()
+}
+
+// restoreRows clears the soft delete column of the deleted rows matching where.
+func restoreRows[T any](ctx context.Context, conn model.Connection, table *Table[T], where Condition) error {
+	info := table.TableInfo
+	if info.softDelete == nil {
+		return model.ErrNoSoftDelete
+	}
+	s := table.UpdateContext(ctx)
+	s.conn = conn
+	s.builder.Changes[table.Field(info.softDelete.ColumnName)] = info.softDeleteValue(false)
+	s.builder.core.Where = And(where, info.softDeleteScope(trashedOnly))
+	return s.Exec()
+}
+
+// softDeleteSql returns the literal soft delete condition for cached SQL,
+// e.g. "deleted_at IS NULL", or an empty string without a soft delete column.
+func (info *TableInfo) softDeleteSql() string {
+	template := info.softDeleteTemplate(trashedExclude)
+	if template == "" {
+		return ""
+	}
+	return fmt.Sprintf(template, info.softDelete.ColumnName)
+}
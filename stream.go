@@ -0,0 +1,157 @@
+package goent
+
+import (
+	"database/sql"
+	"iter"
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/azhai/goent/model"
+)
+
+// streamCursorNo numbers the cursors of Stream, so that streams on the same transaction do not clash.
+var streamCursorNo atomic.Int64
+
+// Stream returns an iterator over the query results in batches of at most batchSize rows,
+// for result sets too large for All. Unlike IterRows it holds no database cursor open
+// between the batches on SQLite and it loads the relations of With for each batch.
+// On PostgreSQL it reads the rows with a DECLARE CURSOR and FETCH loop, in the transaction
+// of OnTransaction or in a new read only transaction. On the other databases it pages by
+// the OrderBy columns followed by the primary key, like KeysetPagination, so the order
+// columns must be selected and not NULL there.
+//
+// Example:
+//
+//	for batch, err := range db.Product.Select().With("Category").OrderBy("id").Stream(500) {
+//		if err != nil {
+//			return err
+//		}
+//		export(batch)
+//	}
+func (s *StateSelect[T, R]) Stream(batchSize int) iter.Seq2[[]*R, error] {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if info := s.table.TableInfo; info.db != nil && info.db.DriverName() == "PostgreSQL" {
+		return s.streamCursor(batchSize)
+	}
+	return s.streamKeyset(batchSize)
+}
+
+// streamCursor yields the batches of a server side cursor, which lives until the end of its transaction.
+func (s *StateSelect[T, R]) streamCursor(size int) iter.Seq2[[]*R, error] {
+	return func(yield func([]*R, error) bool) {
+		defer PutBuilder(s.builder)
+		info := s.table.TableInfo
+		conn, cfg := s.Prepare(info)
+		var tx model.Transaction
+		if s.conn == nil {
+			var err error
+			if tx, err = info.driver.NewTransaction(s.ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+				yield(nil, err)
+				return
+			}
+			conn = tx
+		}
+
+		name := "goent_stream_" + strconv.FormatInt(streamCursorNo.Add(1), 10)
+		declare := model.CreateQuery(s.builder.Build(false))
+		declare.RawSql = "DECLARE " + name + " NO SCROLL CURSOR FOR " + declare.RawSql
+		err, stopped := declare.WrapExec(s.ctx, conn, cfg), false
+		to := s.getFetchFunc()
+		for err == nil {
+			var rows []*R
+			fetch := model.CreateQuery("FETCH FORWARD "+strconv.Itoa(size)+" FROM "+name, nil)
+			if rows, err = s.fetchBatch(conn, cfg, fetch, to); err != nil || len(rows) == 0 {
+				break
+			}
+			if stopped = !yield(rows, nil); stopped || len(rows) < size {
+				break
+			}
+		}
+
+		if tx != nil {
+			if err != nil {
+				_ = tx.Rollback()
+			} else {
+				err = tx.Commit() // which closes the cursor
+			}
+		} else if err == nil {
+			closing := model.CreateQuery("CLOSE "+name, nil)
+			err = closing.WrapExec(s.ctx, conn, cfg)
+		}
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// streamKeyset yields the batches of one query per page, each page starts after the last row of the previous one.
+func (s *StateSelect[T, R]) streamKeyset(size int) iter.Seq2[[]*R, error] {
+	return func(yield func([]*R, error) bool) {
+		defer PutBuilder(s.builder)
+		info := s.table.TableInfo
+		if len(info.PrimaryKeys) == 0 {
+			yield(nil, model.ErrNoPrimaryKey)
+			return
+		}
+		orders := s.keysetOrders()
+		base, limit := s.builder.core.Where, s.builder.core.Limit
+		s.builder.Orders = orders
+		conn, cfg := s.Prepare(info)
+		to := s.getFetchFunc()
+
+		var last []any
+		for remain := limit; ; {
+			page := size
+			if limit > 0 {
+				page = min(size, remain)
+			}
+			if last != nil {
+				s.builder.core.Where = applyFilter(&base, keysetCondition(orders, last, false))
+				s.builder.Offset = 0 // the offset only applies to the first page
+			}
+			s.builder.core.Limit = page
+			s.builder.core.resetBuf()
+			rows, err := s.fetchBatch(conn, cfg, model.CreateQuery(s.builder.Build(false)), to)
+			if err == nil && len(rows) > 0 {
+				last, err = orderValues(info, orders, rows[len(rows)-1])
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(rows) == 0 || !yield(rows, nil) || len(rows) < page {
+				return
+			}
+			if remain -= len(rows); limit > 0 && remain <= 0 {
+				return
+			}
+		}
+	}
+}
+
+// fetchBatch reads the rows of a query, then runs the AfterFind hooks and loads the relations of With.
+func (s *StateSelect[T, R]) fetchBatch(conn model.Connection, cfg *model.DatabaseConfig,
+	qr model.Query, to FetchFunc) (res []*R, err error) {
+	for obj, err := range FetchResult[R](NewHandler(s.ctx, conn, cfg), qr, to) {
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, obj)
+	}
+	if hasHook[R](hookAfterFind) {
+		hctx := hookContext(s.ctx, conn)
+		for _, obj := range res {
+			if err = runHook(hctx, hookAfterFind, obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(res) > 0 && s.sameModel && len(s.withForeigns) > 0 {
+		rows := *(*[]*T)(unsafe.Pointer(&res))
+		err = QueryForeignsByNameContext(s.ctx, s.table, rows, s.withForeigns...)
+	}
+	return res, err
+}
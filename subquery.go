@@ -0,0 +1,58 @@
+package goent
+
+import "github.com/azhai/goent/model"
+
+// Subquery is a SELECT which compiles into a condition of an outer query, see InSelect and Exists.
+// Any StateSelect is a Subquery, it is also accepted as the value of Equals, Greater, Less and the
+// other comparisons, where it must return a single row and column.
+type Subquery interface {
+	subqueryBuilder() *Builder
+}
+
+// subqueryBuilder returns the builder of the SELECT.
+func (s *StateSelect[T, R]) subqueryBuilder() *Builder {
+	return s.builder
+}
+
+// buildSubquery builds the SELECT of the builder for a condition of an outer query.
+// The placeholders are numbered after startIdx, so they carry on the numbering of the outer query,
+// and the columns are qualified with their table name, so correlated conditions which compare
+// them to the columns of the outer query are not ambiguous.
+// It returns the SQL, its arguments and the last placeholder number.
+func (b *Builder) buildSubquery(startIdx int) (string, []any, int) {
+	c := &b.core
+	c.buf.Reset()
+	c.argNo = startIdx
+	args := b.buildNested()
+	return c.buf.String(), args, c.argNo
+}
+
+// buildNested writes the SELECT with qualified columns and no WITH clause,
+// numbering the placeholders after the current argNo.
+func (b *Builder) buildNested() []any {
+	typ := b.Type
+	b.Type = model.SelectJoinQuery
+	defer func() { b.Type = typ }()
+
+	args := b.buildHead()
+	if joinArgs := b.buildJoins(); len(joinArgs) > 0 {
+		args = append(args, joinArgs...)
+	}
+	if whereArgs := b.buildWhere(true); len(whereArgs) > 0 {
+		args = append(args, whereArgs...)
+	}
+	if tailArgs := b.buildTail(); len(tailArgs) > 0 {
+		args = append(args, tailArgs...)
+	}
+	return args
+}
+
+// appendSubquery writes the subquery in parentheses and returns the last placeholder number.
+func (c *BuilderCore) appendSubquery(sub *Builder, startIdx int, args *[]any) int {
+	sql, subArgs, lastIdx := sub.buildSubquery(startIdx)
+	c.buf.WriteByte('(')
+	c.buf.WriteString(sql)
+	c.buf.WriteByte(')')
+	*args = append(*args, subArgs...)
+	return lastIdx
+}
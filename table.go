@@ -37,10 +37,17 @@ type TableInfo struct {
 	selectByPKSql string       // selectByPKSql is the cached SELECT BY primary key SQL.
 	deleteByPKSql string       // deleteByPKSql is the cached DELETE BY primary key SQL.
 	pkField       *Field       // pkField is the cached primary key field.
+	softDelete    *Column      // softDelete is the column tagged with goe:"soft_delete".
+	version       *Column      // version is the optimistic lock column tagged with goe:"version".
 	modelType     reflect.Type // modelType is the reflect.Type of the table's model struct.
 	driver        model.Driver // driver is the database driver for this table.
 	db            *DB          // db is the database connection for this table.
 
+	// softDeleteKind is the kind of value stored in the soft delete column.
+	softDeleteKind softDeleteKind
+	// autoTimes are the columns tagged with auto_create_time or auto_update_time.
+	autoTimes []*autoTime
+
 	// formattedName is the cached driver-formatted full table name (e.g. "public"."status").
 	formattedName string
 	// modelTable is the cached model.Table to avoid allocation on each SetTable call.
@@ -208,7 +215,10 @@ func (info *TableInfo) GetSelectByPKSql() string {
 	info.selectByPKOnce.Do(func() {
 		if len(info.PrimaryKeys) == 1 {
 			pkName := info.PrimaryKeys[0].ColumnName
-			info.selectByPKSql = "SELECT * FROM " + info.getFullName() + " WHERE " + pkName + " = $1"
+			info.selectByPKSql = "SELECT * FROM " + info.GetFormattedName() + " WHERE " + pkName + " = $1"
+			if scope := info.softDeleteSql(); scope != "" {
+				info.selectByPKSql += " AND " + scope
+			}
 		}
 	})
 	return info.selectByPKSql
@@ -218,13 +228,16 @@ func (info *TableInfo) GetDeleteByPKSql() string {
 	info.deleteByPKOnce.Do(func() {
 		if len(info.PrimaryKeys) == 1 {
 			pkName := info.PrimaryKeys[0].ColumnName
-			info.deleteByPKSql = "DELETE FROM " + info.getFullName() + " WHERE " + pkName + " = $1"
+			info.deleteByPKSql = "DELETE FROM " + info.GetFormattedName() + " WHERE " + pkName + " = $1"
 		}
 	})
 	return info.deleteByPKSql
 }
 
 func (info *TableInfo) getRefTableName(foreign *Foreign, fkName string) (string, bool) {
+	if len(foreign.ForeignKeys) > 0 {
+		return foreign.RefType, foreign.RefType != ""
+	}
 	switch foreign.Type {
 	case M2O, O2O:
 		if _, ok := info.Columns[fkName]; !ok {
@@ -270,6 +283,10 @@ type Table[T any] struct {
 	fetchAllOnce sync.Once
 	// fetchByPKOnce ensures fetchByPK/connByPK/cfgByPK are initialized only once.
 	fetchByPKOnce sync.Once
+	// cols is the cached typed column references of TableColumns.
+	cols any
+	// colsOnce ensures cols is built only once.
+	colsOnce sync.Once
 
 	db         *DB // db is the database connection.
 	Model      *T  // Model is the struct type representing the table records.
@@ -332,9 +349,13 @@ func NewTableReflect(db *DB, typeOf reflect.Type, addr uintptr, fieldName, schem
 		Columns:   make(map[string]*Column),
 		Foreigns:  make(map[string]*Foreign),
 		modelType: modelType,
-		driver:    db.driver,
 		db:        db,
 	}
+	if db != nil {
+		info.driver = db.driver
+	}
+	// arrays and ranges are native on PostgreSQL, other drivers store them as JSON text
+	nativeArrays := info.driver != nil && info.driver.Name() == "PostgreSQL"
 
 	// var attr field
 	modelValue = modelValue.Elem()
@@ -351,8 +372,19 @@ func NewTableReflect(db *DB, typeOf reflect.Type, addr uintptr, fieldName, schem
 			columnName = col
 		}
 		defaultValue, hasDefault := utils.GetTagValue(geoTag, "default")
+		isJSON := utils.HasTagValue(geoTag, "json")
+		isArray := !isJSON && (isArrayType(fieldOf.Type) || rangeTypeName(fieldOf.Type) != "")
+		if isArray && !nativeArrays {
+			isJSON = true
+		}
 
-		if fieldKind == reflect.Slice {
+		// Relations on a composite key, e.g. `goe:"o2m;fk=order_id|product_id"`
+		if fkCols := tagColumns(geoTag, "fk"); len(fkCols) > 1 {
+			info.Foreigns[columnName] = newCompositeForeign(fieldOf, geoTag, fkCols)
+			continue
+		}
+
+		if fieldKind == reflect.Slice && !isJSON && !isArray {
 			if utils.HasTagValue(geoTag, "o2m") {
 				fkCol, _ := utils.GetTagValue(geoTag, "fk")
 				// Find the element type name for reference matching
@@ -404,7 +436,7 @@ func NewTableReflect(db *DB, typeOf reflect.Type, addr uintptr, fieldName, schem
 			}
 		}
 
-		if fieldKind == reflect.Pointer {
+		if fieldKind == reflect.Pointer && !isJSON {
 			elemType := fieldOf.Type.Elem()
 			if elemType.Kind() == reflect.Struct && elemType.PkgPath() != "" {
 				if isTableTypeField(elemType) {
@@ -423,13 +455,25 @@ func NewTableReflect(db *DB, typeOf reflect.Type, addr uintptr, fieldName, schem
 			FieldId:      i,
 			tableName:    tableName,
 			schemaName:   &schema,
+			isJSON:       isJSON,
+		}
+		if isJSON {
+			column.ColumnType = "json"
 		}
 		info.Columns[columnName] = column
 		info.ColumnNames = append(info.ColumnNames, columnName)
+		if utils.HasTagValue(geoTag, "soft_delete") {
+			info.setSoftDelete(column, fieldOf.Type)
+		}
+		info.addAutoTime(column, fieldOf.Type, geoTag)
+		if utils.HasTagValue(geoTag, "version") {
+			info.setVersion(column, fieldOf.Type)
+		}
 		info.sortedFields = append(info.sortedFields, &Field{
 			TableAddr:  info.TableAddr,
 			ColumnName: columnName,
 			FieldId:    column.FieldId,
+			isJSON:     isJSON,
 		})
 
 		if strings.EqualFold(fieldOf.Name, "id") || utils.HasTagValue(geoTag, "pk") {
@@ -555,10 +599,37 @@ func (q *TableQuery[T]) Select() *StateSelect[T, T] {
 	return NewStateSelectFrom[T, T](q.state, q.table)
 }
 
+// WithTrashed includes the soft deleted rows in the query results.
+func (q *TableQuery[T]) WithTrashed() *TableQuery[T] {
+	q.state.builder.trashed = trashedInclude
+	return q
+}
+
+// OnlyTrashed limits the query to the soft deleted rows.
+//
+// Example:
+//
+//	count, err := db.User.Filter().OnlyTrashed().Count("id")
+//	err = db.User.Filter().OnlyTrashed().Delete().ForceDelete() // purge deleted rows
+func (q *TableQuery[T]) OnlyTrashed() *TableQuery[T] {
+	q.state.builder.trashed = trashedOnly
+	return q
+}
+
+// Restore clears the soft delete column of the deleted rows matching this query's conditions.
+// Returns model.ErrNoSoftDelete if the table has no goe:"soft_delete" column.
+func (q *TableQuery[T]) Restore() error {
+	return restoreRows(q.state.ctx, q.state.conn, q.table, q.state.builder.core.Where)
+}
+
 // Delete creates a StateDelete from this query's conditions.
+// After OnlyTrashed, it only matches the soft deleted rows.
 func (q *TableQuery[T]) Delete() *StateDelete[T] {
 	s := NewStateDeleteWhere(q.state.ctx)
 	s.builder.core.Where = q.state.builder.core.Where
+	if q.state.builder.trashed == trashedOnly {
+		s.builder.core.Where = And(s.builder.core.Where, q.table.softDeleteScope(trashedOnly))
+	}
 	s.conn = q.state.conn
 	return &StateDelete[T]{table: q.table, StateDeleteWhere: s}
 }
@@ -637,8 +708,9 @@ func (q *TableQuery[T]) Sum(col string) (int64, error) {
 	return aggInt(q.state, q.table, col, "SUM(%s)")
 }
 
+// Avg returns the average of the column truncated to an integer, use AvgFloat for the exact value.
 func (q *TableQuery[T]) Avg(col string) (int64, error) {
-	return aggInt(q.state, q.table, col, "AVG(%s)")
+	return aggAvg(q.state, q.table, col)
 }
 
 func (q *TableQuery[T]) MaxFloat(col string) (float64, error) {
@@ -665,6 +737,11 @@ func (q *TableQuery[T]) ToLower(col string) ([]string, error) {
 	return aggStr(q.state, q.table, col, "LOWER(%s)")
 }
 
+// GroupCount counts the rows matching the query conditions for each value of an integer column.
+func (q *TableQuery[T]) GroupCount(col string) (map[int64]int64, error) {
+	return groupCount[int64](q.state, q.table, col)
+}
+
 // ------------------------------
 // Filter/Where ...
 // ------------------------------
@@ -737,6 +814,16 @@ func (t *Table[T]) DeleteContext(ctx context.Context) *StateDelete[T] {
 	return &StateDelete[T]{table: t, StateDeleteWhere: s}
 }
 
+// Restore clears the soft delete column of the deleted rows matching the conditions.
+// Returns model.ErrNoSoftDelete if the table has no goe:"soft_delete" column.
+//
+// Example:
+//
+//	err := db.User.Restore(goent.Equals(db.User.Field("id"), 1))
+func (t *Table[T]) Restore(conds ...Condition) error {
+	return restoreRows(context.Background(), nil, t, And(conds...))
+}
+
 // Truncate removes all rows from the table and resets auto-increment counters.
 // For PostgreSQL it executes TRUNCATE TABLE ... RESTART IDENTITY;
 // For SQLite it executes DELETE FROM ... and resets the sqlite_sequence.
@@ -806,7 +893,13 @@ func (t *Table[T]) InsertOneContext(ctx context.Context, obj *T) error {
 	// Try fast path: single auto-increment PK, no defaults on non-PK columns
 	if len(t.PrimaryKeys) == 1 && t.PrimaryKeys[0].IsAutoIncr {
 		if sql, fields := t.getInsertOneSql(); sql != "" {
-			return t.insertOneFastPath(ctx, sql, fields, obj)
+			if err := runHook(ctx, hookBeforeInsert, obj); err != nil {
+				return err
+			}
+			if err := t.insertOneFastPath(ctx, sql, fields, obj); err != nil {
+				return err
+			}
+			return runHook(ctx, hookAfterInsert, obj)
 		}
 	}
 	// Fallback to standard path
@@ -868,6 +961,7 @@ func (t *Table[T]) getInsertOneSql() (string, []*Field) {
 
 // insertOneFastPath executes the INSERT using cached SQL, bypassing Builder.
 func (t *Table[T]) insertOneFastPath(ctx context.Context, sql string, fields []*Field, obj *T) error {
+	t.fillAutoTimes(reflect.ValueOf(obj).Elem(), false)
 	var args []any
 	if gen, ok := any(obj).(GenInsertValues); ok {
 		args = gen.InsertValues()
@@ -875,7 +969,7 @@ func (t *Table[T]) insertOneFastPath(ctx context.Context, sql string, fields []*
 		valueOf := reflect.ValueOf(obj).Elem()
 		args = make([]any, len(fields))
 		for i, fld := range fields {
-			args[i] = valueOf.Field(fld.FieldId).Interface()
+			args[i] = fld.arg(valueOf.Field(fld.FieldId).Interface())
 		}
 	}
 
@@ -969,13 +1063,14 @@ func (t *Table[T]) Select(fields ...any) *StateSelect[T, T] {
 // FindByPK selects a single row by primary key using a fast path that bypasses Builder creation.
 // This is the most optimized path for simple primary key lookups - no Builder, no StateSelect,
 // no object allocation beyond the result struct. Uses cached SQL and cached FetchFunc.
-// Only works for tables with a single primary key column.
-func (t *Table[T]) FindByPK(id int64) (*T, error) {
+// Only works for tables with a single primary key column, the key can be of any type
+// the column accepts, e.g. an int64, a string or a uuid.UUID.
+func (t *Table[T]) FindByPK(id any) (*T, error) {
 	return t.FindByPKContext(context.Background(), id)
 }
 
 // FindByPKContext selects a single row by primary key with a specific context.
-func (t *Table[T]) FindByPKContext(ctx context.Context, id int64) (*T, error) {
+func (t *Table[T]) FindByPKContext(ctx context.Context, id any) (*T, error) {
 	sql := t.GetSelectByPKSql()
 	if sql == "" {
 		return nil, model.ErrNoPrimaryKey
@@ -995,9 +1090,33 @@ func (t *Table[T]) FindByPKContext(ctx context.Context, id int64) (*T, error) {
 	if err := row.Scan(t.fetchByPK(target)...); err != nil {
 		return nil, err
 	}
+	if err := runHook(ctx, hookAfterFind, target); err != nil {
+		return nil, err
+	}
 	return target, nil
 }
 
+// FindByKeys selects a single row by its full primary key, which may span several columns.
+// The keys are either one value per primary key column in struct field order,
+// or a single Dict keyed by column name.
+//
+// Example:
+//
+//	item, err := db.OrderItem.FindByKeys(orderID, productID)
+//	item, err := db.OrderItem.FindByKeys(goent.Dict{"order_id": orderID, "product_id": productID})
+func (t *Table[T]) FindByKeys(keys ...any) (*T, error) {
+	return t.FindByKeysContext(context.Background(), keys...)
+}
+
+// FindByKeysContext selects a single row by its full primary key with a specific context.
+func (t *Table[T]) FindByKeysContext(ctx context.Context, keys ...any) (*T, error) {
+	cond, _, err := t.keysCondition(keys)
+	if err != nil {
+		return nil, err
+	}
+	return t.SelectContext(ctx).Filter(cond).One()
+}
+
 // newFetchByPKFunc creates a FetchFunc for the FindByPK fast path.
 // It uses GenScanDest if available, otherwise falls back to reflection.
 func (t *Table[T]) newFetchByPKFunc() FetchFunc {
@@ -1011,7 +1130,7 @@ func (t *Table[T]) newFetchByPKFunc() FetchFunc {
 		valueOf := reflect.ValueOf(target).Elem()
 		dest := make([]any, len(fields))
 		for i, fld := range fields {
-			dest[i] = valueOf.Field(fld.FieldId).Addr().Interface()
+			dest[i] = fld.dest(valueOf.Field(fld.FieldId))
 		}
 		return dest
 	}
@@ -1040,6 +1159,49 @@ func getColumnTypeName(t reflect.Type) string {
 	return resolveTypeName(t)
 }
 
+// tagColumns returns the column list of a goe tag value, columns are separated by "|".
+// Example: tagColumns(`o2m;fk=order_id|product_id`, "fk") returns ["order_id", "product_id"]
+func tagColumns(tag, key string) []string {
+	value, ok := utils.GetTagValue(tag, key)
+	if !ok || value == "" {
+		return nil
+	}
+	cols := strings.Split(value, "|")
+	for i, col := range cols {
+		cols[i] = strings.TrimSpace(col)
+	}
+	return cols
+}
+
+// newCompositeForeign creates a Foreign whose key spans several columns.
+// Slice fields default to O2M, where the columns belong to the child table,
+// pointer fields default to M2O, where the columns belong to this table.
+func newCompositeForeign(fieldOf reflect.StructField, tag string, fkCols []string) *Foreign {
+	elemType := fieldOf.Type
+	fkType := M2O
+	if elemType.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+		fkType = O2M
+	}
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if utils.HasTagValue(tag, "o2o") {
+		fkType = O2O
+	} else if utils.HasTagValue(tag, "o2m") {
+		fkType = O2M
+	} else if utils.HasTagValue(tag, "m2o") {
+		fkType = M2O
+	}
+	return &Foreign{
+		Type:          fkType,
+		MountField:    fieldOf.Name,
+		RefType:       elemType.Name(),
+		ForeignKeys:   fkCols,
+		ReferenceKeys: tagColumns(tag, "ref"),
+	}
+}
+
 func isTableTypeField(t reflect.Type) bool {
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
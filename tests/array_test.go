@@ -0,0 +1,140 @@
+package goent_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestArrayAndRangeColumns verifies that slice and Range fields round trip through insert, update and scan,
+// and the ArrayContains, ArrayOverlaps, Any and RangeContains conditions.
+func TestArrayAndRangeColumns(t *testing.T) {
+	db := setupTables(t, "Booking")
+	var err error
+
+	ada := &Booking{Guest: "ada", Nights: []int64{1, 2, 3}, Rooms: []string{"101", "102"}, Stay: goent.NewRange[int64](1, 4)}
+	if err = db.Booking.Insert().One(ada); err != nil {
+		t.Fatalf("Insert One error: %v", err)
+	}
+	batch := []*Booking{
+		{Guest: "bob", Nights: []int64{5}, Rooms: []string{"201"}, Stay: goent.NewRange[int64](5, 6)},
+		{Guest: "eve"},
+	}
+	if err = db.Booking.Insert().All(true, batch); err != nil {
+		t.Fatalf("Insert All error: %v", err)
+	}
+
+	stored, err := db.Booking.FindByPK(ada.Id)
+	if err != nil {
+		t.Fatalf("FindByPK error: %v", err)
+	}
+	if !slices.Equal(stored.Nights, ada.Nights) || !slices.Equal(stored.Rooms, ada.Rooms) ||
+		stored.Stay.Lower != 1 || stored.Stay.Upper != 4 {
+		t.Errorf("FindByPK: expected the arrays and range back, got %+v", stored)
+	}
+
+	guests := func(step string, cond goent.Condition, want ...string) {
+		t.Helper()
+		rows, err := db.Booking.Select().Filter(cond).OrderBy("id").All()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row.Guest
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+	nights, rooms, stay := db.Booking.Field("nights"), db.Booking.Field("rooms"), db.Booking.Field("stay")
+	guests("ArrayContains", goent.ArrayContains(rooms, []string{"102", "101"}), "ada")
+	guests("ArrayContains missing", goent.ArrayContains(rooms, []string{"101", "201"}))
+	guests("ArrayOverlaps", goent.ArrayOverlaps(rooms, []string{"101", "201"}), "ada", "bob")
+	guests("Any", goent.Any(nights, int64(5)), "bob")
+	guests("RangeContains lower", goent.RangeContains(stay, int64(1)), "ada")
+	guests("RangeContains upper", goent.RangeContains(stay, int64(4)))
+	guests("RangeContains inside", goent.RangeContains(stay, int64(5)), "bob")
+
+	if err = db.Booking.Update().Set(goent.Pair{Key: "rooms", Value: []string{"301"}},
+		goent.Pair{Key: "stay", Value: goent.NewRange[int64](7, 9)}).ByPK(ada.Id); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	stored, err = db.Booking.FindByPK(ada.Id)
+	if err != nil || !slices.Equal(stored.Rooms, []string{"301"}) || stored.Stay.Lower != 7 || stored.Stay.Upper != 9 {
+		t.Errorf("Update: unexpected booking %+v (%v)", stored, err)
+	}
+	list, err := db.Booking.Select().OrderBy("id").All()
+	if err != nil || len(list) != 3 || list[2].Nights != nil || list[2].Stay.Upper != 0 {
+		t.Errorf("Select: unexpected bookings %+v (%v)", list, err)
+	}
+}
+
+// TestTableReflectWithoutDriver verifies that the array and range columns of a table are mapped
+// without a database, they are stored as JSON then.
+func TestTableReflectWithoutDriver(t *testing.T) {
+	_, info := goent.NewTableReflect(nil, reflect.TypeFor[goent.Table[Booking]](), 0, "Booking", "", 0, 0)
+	for _, name := range []string{"nights", "rooms", "stay"} {
+		if info.Columns[name] == nil {
+			t.Errorf("expected the column %s, got %v", name, info.Columns)
+		}
+	}
+}
+
+// TestRangeUnbounded verifies that the unbounded sides of a range round trip through the literal
+// of PostgreSQL and the JSON of SQLite, and that RangeContains matches them.
+func TestRangeUnbounded(t *testing.T) {
+	literals := map[string]goent.Range[int64]{
+		"[5,)":   {Lower: 5, Bounds: "[)", UpperInf: true},
+		"(,10]":  {Upper: 10, Bounds: "(]", LowerInf: true},
+		"(,)":    {Bounds: "()", LowerInf: true, UpperInf: true},
+		"[0,10)": goent.NewRange[int64](0, 10),
+		"empty":  {Bounds: "empty"},
+	}
+	for text, want := range literals {
+		var r goent.Range[int64]
+		if err := r.Scan(text); err != nil || r != want {
+			t.Errorf("Scan %s: expected %+v, got %+v (%v)", text, want, r, err)
+		}
+		if got := want.String(); got != text {
+			t.Errorf("String: expected %s, got %s", text, got)
+		}
+	}
+
+	db := setupTables(t, "Booking")
+	var err error
+
+	rows := []*Booking{
+		{Guest: "from", Stay: goent.Range[int64]{Lower: 10, UpperInf: true}},
+		{Guest: "until", Stay: goent.Range[int64]{Upper: 3, LowerInf: true}},
+		{Guest: "none", Stay: goent.Range[int64]{Bounds: "empty"}},
+	}
+	if err = db.Booking.Insert().All(true, rows); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	// PostgreSQL returns the bounds it normalized, so only the values and the unbounded sides are compared
+	for _, row := range rows[:2] {
+		stored, err := db.Booking.FindByPK(row.Id)
+		if err != nil || stored.Stay.Lower != row.Stay.Lower || stored.Stay.Upper != row.Stay.Upper ||
+			stored.Stay.LowerInf != row.Stay.LowerInf || stored.Stay.UpperInf != row.Stay.UpperInf {
+			t.Errorf("FindByPK: expected %+v, got %+v (%v)", row.Stay, stored, err)
+		}
+	}
+
+	stay := db.Booking.Field("stay")
+	for value, want := range map[int64][]string{100: {"from"}, 10: {"from"}, -50: {"until"}, 3: nil, 0: {"until"}} {
+		list, err := db.Booking.Select().Filter(goent.RangeContains(stay, value)).OrderBy("id").All()
+		if err != nil {
+			t.Fatalf("RangeContains %d error: %v", value, err)
+		}
+		var got []string
+		for _, row := range list {
+			got = append(got, row.Guest)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("RangeContains %d: expected %v, got %v", value, want, got)
+		}
+	}
+}
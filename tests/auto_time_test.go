@@ -0,0 +1,80 @@
+package goent_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azhai/goent"
+)
+
+// TestAutoTimestamps verifies that auto_create_time and auto_update_time columns
+// are filled by the insert, save and update paths.
+func TestAutoTimestamps(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	person := &Person{Name: "Linus"}
+	if err = db.Person.Insert().One(person); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	start := time.Now()
+	past := start.Add(-24 * time.Hour).Truncate(time.Second)
+
+	first := &PersonNote{PersonId: person.Id, Body: "first"}
+	if err = db.PersonNote.Insert().One(first); err != nil {
+		t.Fatalf("Insert One error: %v", err)
+	}
+	fast := &PersonNote{PersonId: person.Id, Body: "fast"}
+	if err = db.PersonNote.InsertOne(fast); err != nil {
+		t.Fatalf("InsertOne error: %v", err)
+	}
+	batch := []*PersonNote{
+		{PersonId: person.Id, Body: "batch"},
+		{PersonId: person.Id, Body: "preset", CreatedAt: past},
+	}
+	if err = db.PersonNote.Insert().All(true, batch); err != nil {
+		t.Fatalf("Insert All error: %v", err)
+	}
+	for _, note := range []*PersonNote{first, fast, batch[0]} {
+		if note.CreatedAt.IsZero() || note.UpdatedAt < start.UnixMilli() {
+			t.Errorf("Insert %q: expected auto times, got %v / %d", note.Body, note.CreatedAt, note.UpdatedAt)
+		}
+	}
+	if !batch[1].CreatedAt.Equal(past) {
+		t.Errorf("Insert must keep a preset create time, got %v", batch[1].CreatedAt)
+	}
+	stored, err := db.PersonNote.FindByPK(first.Id)
+	if err != nil || stored.CreatedAt.IsZero() || stored.UpdatedAt != first.UpdatedAt {
+		t.Fatalf("FindByPK: expected stored auto times, got %+v (%v)", stored, err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "edited"},
+		goent.Pair{Key: "version", Value: stored.Version}).ByPK(first.Id); err != nil {
+		t.Fatalf("Update ByPK error: %v", err)
+	}
+	updated, _ := db.PersonNote.FindByPK(first.Id)
+	if updated == nil || updated.UpdatedAt <= first.UpdatedAt || !updated.CreatedAt.Equal(stored.CreatedAt) {
+		t.Errorf("Update: expected a newer update time only, got %+v (was %+v)", updated, stored)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	before := fast.UpdatedAt
+	fast.Body = "saved"
+	if err = db.PersonNote.Save().One(fast); err != nil {
+		t.Fatalf("Save One error: %v", err)
+	}
+	if fast.UpdatedAt <= before {
+		t.Errorf("Save: expected a newer update time, got %d (was %d)", fast.UpdatedAt, before)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	ids, err := db.PersonNote.Filter(goent.Equals(db.PersonNote.Field("body"), "batch")).
+		UpdateByID().Set(goent.Pair{Key: "body", Value: "rebatched"}).Exec()
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("UpdateByID: expected 1 id, got %v (%v)", ids, err)
+	}
+	if rebatched, _ := db.PersonNote.FindByPK(batch[0].Id); rebatched == nil || rebatched.UpdatedAt <= batch[0].UpdatedAt {
+		t.Errorf("UpdateByID: expected a newer update time, got %+v", rebatched)
+	}
+}
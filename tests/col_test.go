@@ -0,0 +1,83 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// categoryColumns is what goent-gen fields generates for Category,
+// the generator itself is tested against a fixture package in cmd/goent-gen.
+type categoryColumns struct {
+	Id       goent.Col[int]
+	Name     goent.Col[string]
+	ParentId goent.Col[int]
+}
+
+func categoryCols(t *goent.Table[Category]) *categoryColumns {
+	return goent.TableColumns(t, func(info *goent.TableInfo) *categoryColumns {
+		return &categoryColumns{
+			Id:       goent.NewCol[int](info, "id"),
+			Name:     goent.NewCol[string](info, "name"),
+			ParentId: goent.NewCol[int](info, "parent_id"),
+		}
+	})
+}
+
+// TestTypedColumns verifies that typed column references build conditions
+// and are accepted by Select, OrderByCols and GroupByCols.
+func TestTypedColumns(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	rows := []*Category{{Name: "alpha"}, {Name: "beta"}, {Name: "gamma"}, {Name: "delta"}}
+	if err = db.Category.Insert().All(true, rows); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	c := categoryCols(db.Category)
+	if categoryCols(db.Category) != c {
+		t.Error("TableColumns: expected the columns to be built once per table")
+	}
+	if err = db.Category.Filter(c.Name.In("gamma", "delta")).Update().
+		Set(goent.Pair{Key: "parent_id", Value: rows[0].Id}).Exec(); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+
+	names := func(step string, list []*Category, err error, want ...string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(list))
+		for i, row := range list {
+			got[i] = row.Name
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+	list, err := db.Category.Select().Filter(c.ParentId.Equals(rows[0].Id)).OrderByCols(c.Name).All()
+	names("Equals", list, err, "delta", "gamma")
+	list, err = db.Category.Select().Filter(c.Id.Greater(rows[0].Id), c.Name.Like("%a")).
+		OrderByCols(c.Name.Desc()).All()
+	names("Greater and Like", list, err, "gamma", "delta", "beta")
+	list, err = db.Category.Select().Filter(c.Name.NotIn("alpha", "beta")).
+		OrderByCols(c.ParentId, "name DESC").All()
+	names("NotIn", list, err, "gamma", "delta")
+
+	list, err = db.Category.Select(c.Name).Filter(c.Id.Equals(rows[1].Id)).All()
+	if err != nil || len(list) != 1 || list[0].Name != "beta" || list[0].Id != 0 {
+		t.Errorf("Select: expected only the name of beta, got %+v (%v)", list, err)
+	}
+
+	type parentCount struct {
+		ParentId int
+		Count    int64
+	}
+	groups, err := goent.SelectAs[parentCount](db.Category, c.ParentId, goent.CountRows()).
+		GroupByCols(c.ParentId).OrderByCols(c.ParentId).All()
+	if err != nil || len(groups) != 2 || groups[0].Count != 2 || groups[1].Count != 2 {
+		t.Errorf("GroupBy: expected two groups of 2, got %+v (%v)", groups, err)
+	}
+}
@@ -0,0 +1,133 @@
+package goent_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestCompositeKeys verifies lookups, updates and deletes by a composite primary key
+// and eager loading of relations referencing it.
+func TestCompositeKeys(t *testing.T) {
+	db := setupTables(t, "JobReview", "PersonJobTitle", "Person", "JobTitle")
+	var err error
+
+	persons := []*Person{{Name: "Ada"}, {Name: "Alan"}}
+	if err = db.Person.Insert().All(true, persons); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	job := &JobTitle{Name: "Engineer"}
+	if err = db.JobTitle.Insert().One(job); err != nil {
+		t.Fatalf("Insert JobTitle error: %v", err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, p := range persons {
+		err = db.PersonJobTitle.Insert().One(&PersonJobTitle{PersonId: p.Id, JobTitleId: job.Id, CreatedAt: now})
+		if err != nil {
+			t.Fatalf("Insert PersonJobTitle error: %v", err)
+		}
+	}
+	reviews := []*JobReview{
+		{PersonId: persons[0].Id, JobTitleId: job.Id, Note: "good"},
+		{PersonId: persons[0].Id, JobTitleId: job.Id, Note: "great"},
+		{PersonId: persons[1].Id, JobTitleId: job.Id, Note: "fine"},
+	}
+	if err = db.JobReview.Insert().All(true, reviews); err != nil {
+		t.Fatalf("Insert JobReview error: %v", err)
+	}
+
+	found, err := db.PersonJobTitle.FindByKeys(persons[0].Id, job.Id)
+	if err != nil || found.PersonId != persons[0].Id {
+		t.Fatalf("FindByKeys: expected person %d, got %+v (%v)", persons[0].Id, found, err)
+	}
+	keys := goent.Dict{"person_id": persons[1].Id, "job_title_id": job.Id}
+	if found, err = db.PersonJobTitle.Select().ByKeys(keys); err != nil || found.PersonId != persons[1].Id {
+		t.Fatalf("ByKeys with Dict: expected person %d, got %+v (%v)", persons[1].Id, found, err)
+	}
+	if _, err = db.PersonJobTitle.FindByKeys(persons[0].Id); !errors.Is(err, model.ErrKeyValues) {
+		t.Errorf("Expected ErrKeyValues for a partial key, got %v", err)
+	}
+
+	later := now.Add(time.Hour)
+	err = db.PersonJobTitle.Update().Set(goent.Pair{Key: "created_at", Value: later}).ByKeys(persons[0].Id, job.Id)
+	if err != nil {
+		t.Fatalf("Update ByKeys error: %v", err)
+	}
+	if found, _ = db.PersonJobTitle.FindByKeys(persons[0].Id, job.Id); found == nil || !found.CreatedAt.Equal(later) {
+		t.Errorf("Update ByKeys: expected created_at %v, got %+v", later, found)
+	}
+	if found, _ = db.PersonJobTitle.FindByKeys(persons[1].Id, job.Id); found == nil || !found.CreatedAt.Equal(now) {
+		t.Errorf("Update ByKeys must not touch other rows, got %+v", found)
+	}
+
+	links, err := db.PersonJobTitle.Select().With("Reviews").All()
+	if err != nil || len(links) != 2 {
+		t.Fatalf("With Reviews: expected 2 rows, got %d (%v)", len(links), err)
+	}
+	for _, link := range links {
+		want := 1
+		if link.PersonId == persons[0].Id {
+			want = 2
+		}
+		if len(link.Reviews) != want {
+			t.Errorf("With Reviews: person %d expected %d reviews, got %d", link.PersonId, want, len(link.Reviews))
+		}
+	}
+
+	loaded, err := db.JobReview.Select().With("Assignment").All()
+	if err != nil || len(loaded) != 3 {
+		t.Fatalf("With Assignment: expected 3 rows, got %d (%v)", len(loaded), err)
+	}
+	for _, r := range loaded {
+		if r.Assignment == nil || r.Assignment.PersonId != r.PersonId {
+			t.Errorf("With Assignment: review %q has assignment %+v", r.Note, r.Assignment)
+		}
+	}
+
+	db.JobReview.Delete().Exec()
+	if err = db.PersonJobTitle.Delete().ByKeys(persons[0].Id, job.Id); err != nil {
+		t.Fatalf("Delete ByKeys error: %v", err)
+	}
+	if count, _ := db.PersonJobTitle.Count("person_id"); count != 1 {
+		t.Errorf("Delete ByKeys: expected 1 remaining row, got %d", count)
+	}
+}
+
+// TestCompositeForeignKeyMigrate verifies that AutoMigrate creates the foreign key constraint
+// of a relation on a composite key, once.
+func TestCompositeForeignKeyMigrate(t *testing.T) {
+	db := setupTables(t, "JobReview")
+	ctx := context.Background()
+	query := `SELECT COUNT(*) FROM pragma_foreign_key_list('job_review') WHERE "table" = 'person_job_title'`
+	if db.DriverName() == "PostgreSQL" {
+		query = `SELECT COUNT(*) * 2 FROM information_schema.table_constraints
+		WHERE constraint_type = 'FOREIGN KEY' AND constraint_name = 'fk_job_review_person_id_job_title_id'`
+	}
+	rows, err := db.RawQueryContext(ctx, query)
+	if err != nil {
+		t.Fatalf("Query foreign keys error: %v", err)
+	}
+	var columns int
+	for rows.Next() {
+		err = rows.Scan(&columns)
+	}
+	rows.Close()
+	if err != nil || columns != 2 {
+		t.Errorf("Expected a foreign key on (person_id, job_title_id), got %d columns (%v)", columns, err)
+	}
+
+	plan, err := goent.PlanMigrateContext(ctx, db)
+	if err != nil {
+		t.Fatalf("PlanMigrate error: %v", err)
+	}
+	for _, fk := range plan.ForeignKeys {
+		if strings.HasSuffix(fk.Table, "job_review") {
+			t.Errorf("Expected no pending foreign key on job_review, got %+v", fk)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestCompoundSelect verifies UNION, INTERSECT and EXCEPT across tables,
+// with the outer ORDER BY, LIMIT and OFFSET and the Pagination count.
+func TestCompoundSelect(t *testing.T) {
+	db := setupTables(t, "Category", "PersonNote", "Person")
+	var err error
+
+	people := []*Person{{Name: "ada"}, {Name: "grace"}, {Name: "linus"}}
+	if err = db.Person.Insert().All(true, people); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	categories := []*Category{{Name: "books"}, {Name: "grace"}, {Name: "music"}}
+	if err = db.Category.Insert().All(true, categories); err != nil {
+		t.Fatalf("Insert Category error: %v", err)
+	}
+	names := func(step string, rows []*Person, err error, want ...string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row.Name
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+
+	rows, err := db.Person.Select("name").UnionAll(db.Category.Select("name")).OrderBy("name").All()
+	names("Union all", rows, err, "ada", "books", "grace", "grace", "linus", "music")
+
+	rows, err = db.Person.Select("name").Union(db.Category.Select("name")).
+		OrderBy("name DESC").Skip(1).Take(3).All()
+	names("Union with order, offset and limit", rows, err, "linus", "grace", "books")
+
+	rows, err = db.Person.Select("name").Filter(goent.NotEquals(db.Person.Field("name"), "ada")).
+		Union(db.Category.Select("name").Filter(goent.Equals(db.Category.Field("name"), "music"))).
+		OrderBy("name").All()
+	names("Union with arguments", rows, err, "grace", "linus", "music")
+
+	rows, err = db.Person.Select("name").Intersect(db.Category.Select("name")).All()
+	names("Intersect", rows, err, "grace")
+
+	rows, err = db.Person.Select("name").Except(db.Category.Select("name").OrderBy("name").Take(1)).
+		OrderBy("name").All()
+	names("Except ignores the member order and limit", rows, err, "ada", "linus")
+
+	page, err := db.Person.Select("name").Filter(goent.NotEquals(db.Person.Field("name"), "linus")).
+		UnionAll(db.Category.Select("name")).OrderBy("name").Pagination(2, 2)
+	if err != nil {
+		t.Fatalf("Pagination error: %v", err)
+	}
+	if page.TotalValues != 5 || page.TotalPages != 3 {
+		t.Errorf("Pagination: expected 5 values on 3 pages, got %d on %d", page.TotalValues, page.TotalPages)
+	}
+	names("Pagination values", page.Values, nil, "grace", "grace")
+}
@@ -0,0 +1,74 @@
+package goent_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent/model"
+)
+
+// TestCopyFrom verifies that CopyFrom streams rows in several chunks, fills the auto times,
+// joins a transaction and that CopyRows loads rows without a model.
+func TestCopyFrom(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	person := &Person{Name: "Grace"}
+	if err = db.Person.Insert().One(person); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	takeNoteHooks()
+
+	const total = 1234
+	var last *PersonNote
+	n, err := db.PersonNote.CopyFrom(func(yield func(*PersonNote) bool) {
+		for i := range total {
+			last = &PersonNote{PersonId: person.Id, Body: fmt.Sprintf("note %d", i)}
+			if !yield(last) {
+				return
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("CopyFrom error: %v", err)
+	}
+	if n != total {
+		t.Errorf("CopyFrom: expected %d copied rows, got %d", total, n)
+	}
+	if count, _ := db.PersonNote.Count("id"); count != total {
+		t.Errorf("CopyFrom: expected %d rows, got %d", total, count)
+	}
+	if last.CreatedAt.IsZero() || last.UpdatedAt == 0 {
+		t.Errorf("CopyFrom: expected the auto times to be filled, got %+v", last)
+	}
+	if calls := takeNoteHooks(); len(calls) != 0 {
+		t.Errorf("CopyFrom: expected no hooks, got %v", calls)
+	}
+
+	err = db.BeginTransaction(func(tx model.Transaction) error {
+		notes := []*PersonNote{{PersonId: person.Id, Body: "rolled back"}}
+		if _, err := db.PersonNote.Insert().OnTransaction(tx).Copy(slices.Values(notes)); err != nil {
+			return err
+		}
+		return errEmptyNote
+	})
+	if err != errEmptyNote {
+		t.Fatalf("Copy in transaction: expected the rollback error, got %v", err)
+	}
+	if count, _ := db.PersonNote.Count("id"); count != total {
+		t.Errorf("Copy in transaction: expected the rollback to drop the row, got %d rows", count)
+	}
+
+	columns := []string{"person_id", "body", "version", "created_at", "updated_at"}
+	n, err = db.CopyRows(context.Background(), "public", "person_note", columns, func(yield func([]any) bool) {
+		yield([]any{person.Id, "raw", 0, last.CreatedAt, last.UpdatedAt})
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("CopyRows: expected 1 row, got %d (%v)", n, err)
+	}
+	if count, _ := db.PersonNote.Count("id"); count != total+1 {
+		t.Errorf("CopyRows: expected %d rows, got %d", total+1, count)
+	}
+}
@@ -0,0 +1,79 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestCommonTableExpressions verifies recursive, joined and raw CTEs.
+func TestCommonTableExpressions(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	// books -> fiction -> fantasy, books -> science; music is another root
+	books := &Category{Name: "books"}
+	music := &Category{Name: "music"}
+	if err = db.Category.Insert().All(true, []*Category{books, music}); err != nil {
+		t.Fatalf("Insert roots error: %v", err)
+	}
+	fiction := &Category{Name: "fiction", ParentId: books.Id}
+	science := &Category{Name: "science", ParentId: books.Id}
+	if err = db.Category.Insert().All(true, []*Category{fiction, science}); err != nil {
+		t.Fatalf("Insert children error: %v", err)
+	}
+	if err = db.Category.Insert().One(&Category{Name: "fantasy", ParentId: fiction.Id}); err != nil {
+		t.Fatalf("Insert grandchild error: %v", err)
+	}
+	names := func(step string, rows []*Category, err error, want ...string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row.Name
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+
+	tree := goent.NewRecursiveCTE("subtree")
+	tree.UnionAll(
+		db.Category.Select().Filter(goent.Equals(db.Category.Field("id"), fiction.Id)),
+		db.Category.Select().JoinCTE(model.InnerJoin, tree,
+			goent.EqualsField(db.Category.Field("parent_id"), tree.Field("id"))),
+	)
+	rows, err := db.Category.Select().FromCTE(tree).All()
+	names("Recursive subtree", rows, err, "fantasy", "fiction")
+
+	tree = goent.NewRecursiveCTE("subtree")
+	tree.UnionAll(
+		db.Category.Select().Filter(goent.Equals(db.Category.Field("name"), "books")),
+		db.Category.Select().JoinCTE(model.InnerJoin, tree,
+			goent.EqualsField(db.Category.Field("parent_id"), tree.Field("id"))),
+	)
+	rows, err = db.Category.Select().FromCTE(tree).
+		Filter(goent.NotEquals(db.Category.Field("name"), "science")).All()
+	names("Recursive subtree with a filter", rows, err, "books", "fantasy", "fiction")
+	if count, err := db.Category.Select().FromCTE(tree).Count("id"); err != nil || count != 4 {
+		t.Errorf("Count over a CTE: expected 4, got %d (%v)", count, err)
+	}
+
+	roots := goent.NewCTE("root", db.Category.Select("id").Filter(goent.Equals(db.Category.Field("parent_id"), 0)))
+	rows, err = db.Category.Select().
+		JoinCTE(model.InnerJoin, roots, goent.EqualsField(db.Category.Field("parent_id"), roots.Field("id"))).All()
+	names("Join a CTE", rows, err, "fiction", "science")
+
+	depth := goent.NewRawCTE("depth",
+		"SELECT id, 0 AS level FROM category WHERE parent_id = ? UNION ALL "+
+			"SELECT category.id, depth.level + 1 FROM category JOIN depth ON category.parent_id = depth.id WHERE depth.level < ?",
+		0, 1).Recursive()
+	rows, err = db.Category.Select().
+		JoinCTE(model.InnerJoin, depth, goent.EqualsField(db.Category.Field("id"), depth.Field("id"))).All()
+	names("Raw recursive CTE", rows, err, "books", "fiction", "music", "science")
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -149,9 +150,61 @@ type Flag struct {
 }
 
 type Person struct {
-	Id   int `goe:"pk"`
-	Name string
-	Jobs []JobTitle
+	Id    int `goe:"pk"`
+	Name  string
+	Jobs  []JobTitle
+	Notes []*PersonNote `goe:"o2m;fk=person_id"`
+}
+
+// PersonNote is a note about a person, deleted notes are kept with their deletion time.
+type PersonNote struct {
+	Id        int `goe:"pk"`
+	PersonId  int
+	Body      string
+	Version   int        `goe:"version"`
+	CreatedAt time.Time  `goe:"auto_create_time"`
+	UpdatedAt int64      `goe:"auto_update_time:milli"`
+	DeletedAt *time.Time `goe:"soft_delete"`
+}
+
+// Category is a node of a category tree, a root category has a zero parent.
+type Category struct {
+	Id       int `goe:"pk"`
+	Name     string
+	ParentId int
+}
+
+// Document has JSON columns, stored as jsonb on PostgreSQL and as TEXT on SQLite.
+type Document struct {
+	Id    int `goe:"pk"`
+	Title string
+	Meta  DocumentMeta   `goe:"json"`
+	Attrs map[string]any `goe:"json"`
+	Tags  []string       `goe:"json"`
+	Draft *DocumentMeta  `goe:"json"`
+}
+
+// Booking has array and range columns, native on PostgreSQL and JSON text on SQLite.
+type Booking struct {
+	Id     int `goe:"pk"`
+	Guest  string
+	Nights []int64
+	Rooms  []string
+	Stay   goent.Range[int64]
+}
+
+// Article has full-text search columns, a GIN index on PostgreSQL and a FTS5 table on SQLite.
+type Article struct {
+	Id    int    `goe:"pk"`
+	Title string `goe:"fulltext"`
+	Body  string `goe:"fulltext"`
+}
+
+// DocumentMeta is the JSON object of a document.
+type DocumentMeta struct {
+	Author string   `json:"author"`
+	Pages  int      `json:"pages"`
+	Topics []string `json:"topics,omitempty"`
 }
 
 // PersonJobTitle is the relationship between a person and a job title.
@@ -159,6 +212,16 @@ type PersonJobTitle struct {
 	PersonId   int `goe:"pk"`
 	JobTitleId int `goe:"pk"`
 	CreatedAt  time.Time
+	Reviews    []*JobReview `goe:"o2m;fk=person_id|job_title_id"`
+}
+
+// JobReview is a review of a job held by a person, it references the composite key of PersonJobTitle.
+type JobReview struct {
+	Id         int `goe:"pk"`
+	PersonId   int
+	JobTitleId int
+	Note       string
+	Assignment *PersonJobTitle `goe:"m2o;fk=person_id|job_title_id"`
 }
 
 // JobTitle is a job title that a person can have.
@@ -230,8 +293,14 @@ type OtherSchema struct {
 	Status         *goent.Table[Status]
 	Weather        *goent.Table[Weather]
 	Person         *goent.Table[Person]
+	PersonNote     *goent.Table[PersonNote]
+	Category       *goent.Table[Category]
+	Document       *goent.Table[Document]
+	Booking        *goent.Table[Booking]
+	Article        *goent.Table[Article]
 	PersonJobTitle *goent.Table[PersonJobTitle]
 	JobTitle       *goent.Table[JobTitle]
+	JobReview      *goent.Table[JobReview]
 	Exam           *goent.Table[Exam]
 	Select         *goent.Table[Select]
 	Page           *goent.Table[Page]
@@ -266,6 +335,13 @@ func init() {
 	env = environ.NewEnvWithFile("../.env")
 }
 
+// pgTables lists the tables of Database on PostgreSQL, which are truncated before the tests and dropped after them.
+const pgTables = `public.animals, public.person_job_title, public.job_review, public.person_note, public.category,
+	public.document, public.booking, public.article, public.person, public.job_title,
+	public.weather, public.info, public.status, public.default, public.exam, public.page,
+	public.select, public.animal_food, auth.user, auth.role, auth.user_role,
+	food.food, food.habitat, flag.flag, drop.drop`
+
 func TestMain(m *testing.M) {
 	// Setup database connection before tests
 	var err error
@@ -276,12 +352,7 @@ func TestMain(m *testing.M) {
 
 	// Clean up data before tests
 	if db != nil && db.DriverName() == "PostgreSQL" {
-		sql := `
-		TRUNCATE TABLE public.animals, public.person_job_title, public.person, public.job_title,
-		public.weather, public.info, public.status, public.default, public.exam, public.page,
-		public.select, public.animal_food, auth.user, auth.role, auth.user_role,
-		food.food, food.habitat, flag.flag, drop.drop RESTART IDENTITY CASCADE;
-		`
+		sql := "TRUNCATE TABLE " + pgTables + " RESTART IDENTITY CASCADE;"
 		_ = db.DB.RawExecContext(context.Background(), sql)
 	}
 
@@ -290,13 +361,8 @@ func TestMain(m *testing.M) {
 	// Clean up after tests
 	if db != nil {
 		if db.DriverName() == "PostgreSQL" {
-			sql := `
-			DROP TABLE IF EXISTS public.animals, public.person_job_title, public.person, public.job_title,
-			public.weather, public.info, public.status, public.default, public.exam, public.page,
-			public.select, public.animal_food, auth.user, auth.role, auth.user_role,
-			food.food, food.habitat, flag.flag, drop.drop CASCADE;
-			DROP SCHEMA IF EXISTS food, auth, flag, drop CASCADE;
-			`
+			sql := "DROP TABLE IF EXISTS " + pgTables + " CASCADE;\n" +
+				"DROP SCHEMA IF EXISTS food, auth, flag, drop CASCADE;"
 			_ = db.DB.RawExecContext(context.Background(), sql)
 		}
 		_ = goent.Close(db)
@@ -353,12 +419,7 @@ func setupPostgres(drv model.Driver, logFile string) (*Database, error) {
 	if err != nil {
 		return nil, err
 	}
-	sql := `
-	TRUNCATE TABLE public.animals, public.person_job_title, public.person, public.job_title,
-	public.weather, public.info, public.status, public.default, public.exam, public.page,
-	public.select, public.animal_food, auth.user, auth.role, auth.user_role,
-	food.food, food.habitat, flag.flag, drop.drop RESTART IDENTITY CASCADE;
-	`
+	sql := "TRUNCATE TABLE " + pgTables + " RESTART IDENTITY CASCADE;"
 	err = db.DB.RawExecContext(context.Background(), sql)
 	if err != nil {
 		return nil, err
@@ -366,6 +427,38 @@ func setupPostgres(drv model.Driver, logFile string) (*Database, error) {
 	return db, nil
 }
 
+// setupTables returns the test database for a test of the named tables of Database, which are
+// emptied before and after the test in the given order, the referencing tables first.
+// A table missing from the registry fails the test, its relations and conditions would not resolve.
+func setupTables(t *testing.T, tables ...string) *Database {
+	t.Helper()
+	db, err := Setup()
+	if err != nil {
+		t.Skipf("Skipping test: database setup failed: %v", err)
+	}
+	infos := make([]*goent.TableInfo, len(tables))
+	for i, name := range tables {
+		field := reflect.ValueOf(db).Elem().FieldByName(name)
+		if !field.IsValid() || field.IsNil() {
+			t.Fatalf("Database has no table %s", name)
+		}
+		infos[i] = field.Elem().FieldByName("TableInfo").Interface().(*goent.TableInfo)
+		if goent.GetTableInfo(infos[i].TableAddr) == nil {
+			t.Fatalf("Table %s is not registered, an earlier test reset the table registry", name)
+		}
+	}
+	cleanup := func() {
+		for _, info := range infos {
+			if err := db.RawExecContext(context.Background(), "DELETE FROM "+info.GetFormattedName()); err != nil {
+				t.Errorf("Empty table %s error: %v", info.TableName, err)
+			}
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+	return db
+}
+
 func SetupSqlite(dbDSN, logFile string) (*Database, error) {
 	if dbDSN == "" {
 		dbDSN = filepath.Join(os.TempDir(), "goent.db")
@@ -0,0 +1,130 @@
+package goent_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestFullTextSearch verifies the Match condition and the MatchRank ordering on goe:"fulltext" columns,
+// and that the search follows inserts, updates and deletes.
+func TestFullTextSearch(t *testing.T) {
+	db := setupTables(t, "Article")
+	var err error
+
+	rows := []*Article{
+		{Title: "Red running shoes", Body: "Light shoes for the road"},
+		{Title: "Blue hat", Body: "A red hat, a red scarf and red shoes"},
+		{Title: "Green scarf", Body: "Warm wool"},
+	}
+	if err = db.Article.Insert().All(true, rows); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	title, body := db.Article.Field("title"), db.Article.Field("body")
+	titles := func(step string, cond goent.Condition, order any, want ...string) {
+		t.Helper()
+		list, err := db.Article.Select().Filter(cond).OrderByCols(order).All()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(list))
+		for i, row := range list {
+			got[i] = row.Title
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+	titles("Match", goent.Match(title, "shoes"), "id", "Red running shoes")
+	titles("Match all words", goent.Match(body, "red shoes"), "id", "Blue hat")
+	titles("Match case", goent.Match(title, "GREEN"), "id", "Green scarf")
+	titles("Match missing", goent.Match(title, "boots"), "id")
+	titles("Match empty", goent.Match(title, "  "), "id")
+	titles("Match or", goent.Or(goent.Match(title, "red"), goent.Match(body, "red")), "id",
+		"Red running shoes", "Blue hat")
+	rank := &goent.Order{Field: goent.MatchRank(body, "red"), Desc: true}
+	titles("MatchRank", goent.Or(goent.Match(title, "red"), goent.Match(body, "red")), rank,
+		"Blue hat", "Red running shoes")
+
+	if err = db.Article.Update().Set(goent.Pair{Key: "title", Value: "Green boots"}).ByPK(rows[2].Id); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	titles("Match after update", goent.Match(title, "boots"), "id", "Green boots")
+	titles("Match old title", goent.Match(title, "scarf"), "id")
+	if err = db.Article.Delete().ByPK(rows[0].Id); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	titles("Match after delete", goent.Match(title, "shoes"), "id")
+}
+
+// TestFullTextKey verifies that Match fails the statement on a table without a single integer primary key.
+func TestFullTextKey(t *testing.T) {
+	db := setupTables(t, "PersonJobTitle")
+	var err error
+	if db.DriverName() == "PostgreSQL" {
+		t.Skip("Skipping test: the FTS5 table is only used on SQLite")
+	}
+	cond := goent.Match(db.PersonJobTitle.Field("person_id"), "ada")
+	if _, err = db.PersonJobTitle.Select().Filter(cond).All(); !errors.Is(err, model.ErrFullTextKey) {
+		t.Errorf("Match: expected ErrFullTextKey, got %v", err)
+	}
+}
+
+// TestFullTextMigrate verifies that AutoMigrate keeps the FTS5 table of SQLite once it matches the
+// goe:"fulltext" columns and rebuilds it, with its triggers, when the columns changed.
+func TestFullTextMigrate(t *testing.T) {
+	db := setupTables(t, "Article")
+	var err error
+	if db.DriverName() == "PostgreSQL" {
+		t.Skip("Skipping test: the FTS5 table is only used on SQLite")
+	}
+
+	ctx := context.Background()
+	planned := func(step string) bool {
+		t.Helper()
+		plan, err := goent.PlanMigrateContext(ctx, db)
+		if err != nil {
+			t.Fatalf("%s: PlanMigrate error: %v", step, err)
+		}
+		for _, change := range plan.IndexChanges {
+			if change.Index == "article_fts" {
+				return true
+			}
+		}
+		return false
+	}
+	if planned("Migrated") {
+		t.Error("Migrated: expected no change of the FTS5 table")
+	}
+
+	// an FTS5 table of an older model, which only searched the title
+	err = db.RawExecContext(ctx, `DROP TRIGGER article_fts_ai; DROP TRIGGER article_fts_ad; DROP TRIGGER article_fts_au;
+		DROP TABLE article_fts;
+		CREATE VIRTUAL TABLE article_fts USING fts5(title, content=article, content_rowid=id);
+		CREATE TRIGGER article_fts_ai AFTER INSERT ON article BEGIN
+			INSERT INTO article_fts(rowid, title) VALUES (new.id, new.title); END;`)
+	if err != nil {
+		t.Fatalf("Create old FTS5 table error: %v", err)
+	}
+	if !planned("Changed columns") {
+		t.Error("Changed columns: expected a rebuild of the FTS5 table")
+	}
+	if err = goent.AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate error: %v", err)
+	}
+	if planned("Rebuilt") {
+		t.Error("Rebuilt: expected no change of the FTS5 table")
+	}
+
+	if err = db.Article.Insert().One(&Article{Title: "Blue hat", Body: "A warm wool hat"}); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	list, err := db.Article.Select().Filter(goent.Match(db.Article.Field("body"), "wool")).All()
+	if err != nil || len(list) != 1 || list[0].Title != "Blue hat" {
+		t.Errorf("Match after rebuild: unexpected articles %+v (%v)", list, err)
+	}
+}
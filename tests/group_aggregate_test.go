@@ -0,0 +1,73 @@
+package goent_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestGroupedAggregates verifies grouped counts into maps and several aggregates per group into a struct.
+func TestGroupedAggregates(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	books := &Category{Name: "books"}
+	music := &Category{Name: "music"}
+	if err = db.Category.Insert().All(true, []*Category{books, music}); err != nil {
+		t.Fatalf("Insert roots error: %v", err)
+	}
+	children := []*Category{
+		{Name: "science", ParentId: books.Id},
+		{Name: "art", ParentId: books.Id},
+		{Name: "jazz", ParentId: music.Id},
+	}
+	if err = db.Category.Insert().All(true, children); err != nil {
+		t.Fatalf("Insert children error: %v", err)
+	}
+
+	counts, err := db.Category.GroupCount("parent_id")
+	want := map[int64]int64{0: 2, int64(books.Id): 2, int64(music.Id): 1}
+	if err != nil || !maps.Equal(counts, want) {
+		t.Errorf("GroupCount: expected %v, got %v (%v)", want, counts, err)
+	}
+	names, err := goent.GroupCountBy[string](db.Category.Select().
+		Filter(goent.Greater(db.Category.Field("parent_id"), 0)).OrderBy("id").Take(1), "name")
+	if err != nil || !maps.Equal(names, map[string]int64{"science": 1, "art": 1, "jazz": 1}) {
+		t.Errorf("GroupCountBy with a filter: got %v (%v)", names, err)
+	}
+	if _, err = db.Category.GroupCount("missing"); err == nil {
+		t.Error("GroupCount of a missing column: expected an error")
+	}
+
+	type stats struct {
+		ParentId int
+		Count    int64
+		Names    int64
+		MinId    int64
+		MaxId    int64
+		SumId    int64
+		AvgId    float64
+	}
+	id := db.Category.Field("id")
+	rows, err := goent.SelectAs[stats](db.Category, "parent_id", goent.CountRows(),
+		goent.CountDistinct(db.Category.Field("name")), goent.Min(id), goent.Max(id), goent.Sum(id), goent.Avg(id)).
+		Filter(goent.Greater(db.Category.Field("parent_id"), 0)).GroupBy("parent_id").OrderBy("parent_id").All()
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("Aggregates per group: expected 2 rows, got %d (%v)", len(rows), err)
+	}
+	science, art := int64(children[0].Id), int64(children[1].Id)
+	expect := stats{books.Id, 2, 2, science, art, science + art, float64(science+art) / 2}
+	if *rows[0] != expect {
+		t.Errorf("Aggregates per group: expected %+v, got %+v", expect, *rows[0])
+	}
+
+	avg, err := db.Category.Select().Filter(goent.Equals(db.Category.Field("parent_id"), books.Id)).AvgFloat("id")
+	if err != nil || avg != expect.AvgId {
+		t.Errorf("AvgFloat: expected %v, got %v (%v)", expect.AvgId, avg, err)
+	}
+	truncated, err := db.Category.Select().Filter(goent.Equals(db.Category.Field("parent_id"), books.Id)).Avg("id")
+	if err != nil || truncated != int64(expect.AvgId) {
+		t.Errorf("Avg: expected %d, got %d (%v)", int64(expect.AvgId), truncated, err)
+	}
+}
@@ -0,0 +1,83 @@
+package goent_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestGroupByHaving verifies HAVING conditions on aggregates, their argument numbering
+// after the WHERE arguments and their combination with RollUP.
+func TestGroupByHaving(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	books := &Category{Name: "books"}
+	music := &Category{Name: "music"}
+	if err = db.Category.Insert().All(true, []*Category{books, music}); err != nil {
+		t.Fatalf("Insert roots error: %v", err)
+	}
+	children := []*Category{
+		{Name: "science", ParentId: books.Id},
+		{Name: "art", ParentId: books.Id},
+		{Name: "fiction", ParentId: books.Id},
+		{Name: "jazz", ParentId: music.Id},
+	}
+	if err = db.Category.Insert().All(true, children); err != nil {
+		t.Fatalf("Insert children error: %v", err)
+	}
+
+	type groupCount struct {
+		ParentId *int
+		Count    int64
+	}
+	count := &goent.Field{ColumnName: "*", FieldId: -1, Function: "COUNT(%s)"}
+	groups := func(step string, rows []*groupCount, err error, want ...string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			parent := "total"
+			if row.ParentId != nil {
+				parent = fmt.Sprint(*row.ParentId)
+			}
+			got[i] = fmt.Sprintf("%s:%d", parent, row.Count)
+		}
+		slices.Sort(got)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+	root, book, song := "0:", fmt.Sprintf("%d:", books.Id), fmt.Sprintf("%d:", music.Id)
+
+	rows, err := goent.SelectAs[groupCount](db.Category, "parent_id", count).
+		GroupBy("parent_id").Having(goent.Greater(count, 1)).All()
+	groups("Having count", rows, err, root+"2", book+"3")
+
+	rows, err = goent.SelectAs[groupCount](db.Category, "parent_id", count).
+		Filter(goent.NotEquals(db.Category.Field("name"), "art")).
+		GroupBy("parent_id").Having(goent.Greater(count, 1)).
+		Having(goent.LessEquals(db.Category.Field("id").Func("SUM(%s)", ""), books.Id+music.Id)).All()
+	groups("Having after where arguments", rows, err, root+"2")
+
+	rows, err = goent.SelectAs[groupCount](db.Category, "parent_id", count).
+		GroupBy("parent_id").RollUP().Having(goent.Greater(count, 1)).All()
+	groups("Having with RollUP", rows, err, root+"2", book+"3", "total:6")
+
+	rows, err = goent.SelectAs[groupCount](db.Category, "parent_id", count).
+		Filter(goent.Greater(db.Category.Field("parent_id"), 0)).
+		GroupBy("parent_id").RollUP().OrderBy("parent_id").All()
+	groups("RollUP with a filter", rows, err, book+"3", song+"1", "total:4")
+
+	_, err = goent.SelectAs[groupCount](db.Category, "parent_id", count).Having(goent.Greater(count, 1)).All()
+	if !errors.Is(err, model.ErrHavingNoGroupBy) {
+		t.Errorf("Having without GroupBy: expected ErrHavingNoGroupBy, got %v", err)
+	}
+}
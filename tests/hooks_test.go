@@ -0,0 +1,189 @@
+package goent_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+var errEmptyNote = errors.New("note body is empty")
+
+// noteHooks records the hooks called on PersonNote, with a "+tx" suffix inside a transaction.
+var noteHooks struct {
+	sync.Mutex
+	calls []string
+}
+
+func recordNoteHook(ctx context.Context, name string) {
+	if _, ok := goent.TransactionFromContext(ctx); ok {
+		name += "+tx"
+	}
+	noteHooks.Lock()
+	noteHooks.calls = append(noteHooks.calls, name)
+	noteHooks.Unlock()
+}
+
+// rowHookName marks a row hook called on a zero value, which must not happen.
+func rowHookName(name string, n *PersonNote) string {
+	if n.Id == 0 {
+		return name + "(zero)"
+	}
+	return name
+}
+
+func takeNoteHooks() []string {
+	noteHooks.Lock()
+	defer noteHooks.Unlock()
+	calls := noteHooks.calls
+	noteHooks.calls = nil
+	return calls
+}
+
+func (n *PersonNote) BeforeInsert(ctx context.Context) error {
+	recordNoteHook(ctx, "BeforeInsert")
+	if n.Body == "" {
+		return errEmptyNote
+	}
+	return nil
+}
+
+func (n *PersonNote) AfterInsert(ctx context.Context) error {
+	recordNoteHook(ctx, "AfterInsert")
+	return nil
+}
+
+func (n *PersonNote) BeforeUpdate(ctx context.Context) error {
+	recordNoteHook(ctx, rowHookName("BeforeUpdate", n))
+	return nil
+}
+
+func (n *PersonNote) AfterUpdate(ctx context.Context) error {
+	recordNoteHook(ctx, rowHookName("AfterUpdate", n))
+	return nil
+}
+
+func (n *PersonNote) BeforeDelete(ctx context.Context) error {
+	recordNoteHook(ctx, rowHookName("BeforeDelete", n))
+	return nil
+}
+
+func (n *PersonNote) AfterDelete(ctx context.Context) error {
+	recordNoteHook(ctx, rowHookName("AfterDelete", n))
+	return nil
+}
+
+func (n *PersonNote) BeforeUpdateStatement(ctx context.Context, stmt *goent.HookStatement) error {
+	recordNoteHook(ctx, "BeforeUpdateStatement")
+	return nil
+}
+
+func (n *PersonNote) AfterUpdateStatement(ctx context.Context, stmt *goent.HookStatement) error {
+	recordNoteHook(ctx, fmt.Sprintf("AfterUpdateStatement:%d", stmt.Affected))
+	return nil
+}
+
+func (n *PersonNote) BeforeDeleteStatement(ctx context.Context, stmt *goent.HookStatement) error {
+	recordNoteHook(ctx, "BeforeDeleteStatement")
+	return nil
+}
+
+func (n *PersonNote) AfterDeleteStatement(ctx context.Context, stmt *goent.HookStatement) error {
+	recordNoteHook(ctx, fmt.Sprintf("AfterDeleteStatement:%d", stmt.Affected))
+	return nil
+}
+
+func (n *PersonNote) AfterFind(ctx context.Context) error {
+	recordNoteHook(ctx, "AfterFind")
+	return nil
+}
+
+// TestModelHooks verifies that the lifecycle hooks of a model run around its statements,
+// that a Before hook error aborts the statement and that hooks see the transaction.
+// Row hooks run on real rows, statements without a row run the statement hooks.
+func TestModelHooks(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	person := &Person{Name: "Edsger"}
+	if err = db.Person.Insert().One(person); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	expect := func(step string, want ...string) {
+		t.Helper()
+		if got := takeNoteHooks(); !slices.Equal(got, want) {
+			t.Errorf("%s: expected hooks %v, got %v", step, want, got)
+		}
+	}
+	takeNoteHooks()
+
+	err = db.PersonNote.Insert().One(&PersonNote{PersonId: person.Id})
+	if !errors.Is(err, errEmptyNote) {
+		t.Fatalf("Expected the BeforeInsert error, got %v", err)
+	}
+	expect("Aborted insert", "BeforeInsert")
+	if count, _ := db.PersonNote.Count("id"); count != 0 {
+		t.Errorf("Aborted insert: expected no row, got %d", count)
+	}
+
+	note := &PersonNote{PersonId: person.Id, Body: "hello"}
+	if err = db.PersonNote.Insert().One(note); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	expect("Insert", "BeforeInsert", "AfterInsert")
+
+	if _, err = db.PersonNote.FindByPK(note.Id); err != nil {
+		t.Fatalf("FindByPK error: %v", err)
+	}
+	if _, err = db.PersonNote.Select().All(); err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	expect("Find", "AfterFind", "AfterFind")
+
+	note.Body = "saved"
+	if err = db.PersonNote.Save().One(note); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	expect("Save", "BeforeUpdate", "AfterUpdate")
+
+	err = db.BeginTransaction(func(tx model.Transaction) error {
+		return db.PersonNote.Update().OnTransaction(tx).Set(goent.Pair{Key: "body", Value: "in tx"},
+			goent.Pair{Key: "version", Value: note.Version}).ByPK(note.Id)
+	})
+	if err != nil {
+		t.Fatalf("Update in transaction error: %v", err)
+	}
+	expect("Update in transaction", "BeforeUpdate+tx", "AfterUpdate+tx")
+
+	other := &PersonNote{PersonId: person.Id, Body: "other"}
+	if err = db.PersonNote.Insert().One(other); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	takeNoteHooks()
+	err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "all"}).
+		Filter(goent.Equals(db.PersonNote.Field("person_id"), person.Id)).Exec()
+	if err != nil {
+		t.Fatalf("Update Exec error: %v", err)
+	}
+	expect("Update Exec", "BeforeUpdateStatement", "AfterUpdateStatement:2")
+
+	if err = db.PersonNote.Delete().ByPK(note.Id); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	expect("Delete", "BeforeDelete", "AfterDelete")
+	if err = db.PersonNote.Delete().ByPK(note.Id); err != nil {
+		t.Fatalf("Delete again error: %v", err)
+	}
+	expect("Delete again")
+
+	err = db.PersonNote.Delete().Filter(goent.Equals(db.PersonNote.Field("person_id"), person.Id)).Exec()
+	if err != nil {
+		t.Fatalf("Delete Exec error: %v", err)
+	}
+	expect("Delete Exec", "BeforeDeleteStatement", "AfterDeleteStatement:1")
+}
@@ -0,0 +1,58 @@
+package goent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestQueryInto verifies that raw queries and selects scan into DTO structs by column name,
+// skipping unknown columns and leaving the fields without a column at their zero value.
+func TestQueryInto(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	rows := []*Category{{Name: "tools", ParentId: 0}, {Name: "saws", ParentId: 1}, {Name: "drills", ParentId: 1}}
+	if err = db.Category.Insert().All(true, rows); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+
+	type audit struct {
+		Missing string
+	}
+	type parentTotal struct {
+		audit
+		Parent int   `goe:"column:parent_id"`
+		Total  int64 `goe:"column:n"`
+		Note   string
+	}
+	ctx := context.Background()
+	totals, err := goent.QueryInto[parentTotal](ctx, db.DB,
+		"SELECT parent_id, COUNT(*) AS n, MAX(name) AS unknown FROM category WHERE parent_id IN (?, ?) GROUP BY parent_id ORDER BY parent_id",
+		0, 1)
+	if err != nil {
+		t.Fatalf("QueryInto error: %v", err)
+	}
+	if len(totals) != 2 || totals[0].Parent != 0 || totals[0].Total != 1 || totals[1].Parent != 1 ||
+		totals[1].Total != 2 || totals[1].Note != "" || totals[1].Missing != "" {
+		t.Errorf("QueryInto: unexpected totals %+v", totals)
+	}
+
+	type categoryName struct {
+		ParentId int
+		Name     string
+	}
+	names, err := goent.Into[categoryName](db.Category.Select("name", "parent_id").
+		Filter(goent.Equals(db.Category.Field("parent_id"), 1)).OrderBy("name"))
+	if err != nil {
+		t.Fatalf("Into error: %v", err)
+	}
+	if len(names) != 2 || names[0].Name != "drills" || names[0].ParentId != 1 || names[1].Name != "saws" {
+		t.Errorf("Into: unexpected names %+v", names)
+	}
+
+	if _, err = goent.QueryInto[int](ctx, db.DB, "SELECT 1"); err == nil {
+		t.Errorf("QueryInto: expected an error for a non struct type")
+	}
+}
@@ -0,0 +1,99 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestJSONColumns verifies that goe:"json" columns round trip through insert, update and scan,
+// and the JSONPath, JSONContains and JSONHasKey conditions.
+func TestJSONColumns(t *testing.T) {
+	db := setupTables(t, "Document")
+	var err error
+
+	guide := &Document{
+		Title: "guide",
+		Meta:  DocumentMeta{Author: "ada", Pages: 12, Topics: []string{"go", "sql"}},
+		Attrs: map[string]any{"color": "red", "size": 3, "note": nil, "dims": map[string]any{"w": 2, "h": 5}},
+		Tags:  []string{"sale", "new"},
+	}
+	if err = db.Document.Insert().One(guide); err != nil {
+		t.Fatalf("Insert One error: %v", err)
+	}
+	batch := []*Document{
+		{Title: "manual", Meta: DocumentMeta{Author: "bob", Pages: 40},
+			Attrs: map[string]any{"color": "blue"}, Tags: []string{"old"},
+			Draft: &DocumentMeta{Author: "bob", Pages: 1}},
+		{Title: "empty"},
+	}
+	if err = db.Document.Insert().All(true, batch); err != nil {
+		t.Fatalf("Insert All error: %v", err)
+	}
+
+	stored, err := db.Document.FindByPK(guide.Id)
+	if err != nil {
+		t.Fatalf("FindByPK error: %v", err)
+	}
+	if stored.Meta.Author != "ada" || !slices.Equal(stored.Meta.Topics, guide.Meta.Topics) ||
+		stored.Attrs["color"] != "red" || !slices.Equal(stored.Tags, guide.Tags) || stored.Draft != nil {
+		t.Errorf("FindByPK: expected the JSON values back, got %+v", stored)
+	}
+	list, err := db.Document.Select().OrderBy("id").All()
+	if err != nil || len(list) != 3 || list[1].Draft == nil || list[1].Draft.Pages != 1 ||
+		list[2].Attrs != nil || list[2].Tags != nil {
+		t.Fatalf("Select: unexpected documents %+v (%v)", list, err)
+	}
+
+	titles := func(step string, cond goent.Condition, want ...string) {
+		t.Helper()
+		rows, err := db.Document.Select().Filter(cond).OrderBy("id").All()
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row.Title
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+	meta, attrs, tags := db.Document.Field("meta"), db.Document.Field("attrs"), db.Document.Field("tags")
+	titles("JSONPath", goent.Equals(goent.JSONPath(meta, "$.author"), "bob"), "manual")
+	titles("JSONPath index", goent.Equals(goent.JSONPath(meta, "$.topics[1]"), "sql"), "guide")
+	titles("JSONHasKey", goent.JSONHasKey(attrs, "note"), "guide")
+	titles("JSONHasKey missing", goent.JSONHasKey(attrs, "weight"))
+	titles("JSONContains element", goent.JSONContains(tags, "old"), "manual")
+	titles("JSONContains array", goent.JSONContains(tags, []string{"new", "sale"}), "guide")
+	titles("JSONContains object", goent.JSONContains(attrs, map[string]any{"color": "red", "size": 3}), "guide")
+	titles("JSONContains nested", goent.JSONContains(meta, map[string]any{"topics": []string{"go", "sql"}}), "guide")
+	titles("JSONContains nested array subset", goent.JSONContains(meta, map[string]any{"topics": []string{"sql"}}), "guide")
+	titles("JSONContains nested object subset", goent.JSONContains(attrs, map[string]any{"dims": map[string]any{"w": 2}}), "guide")
+	titles("JSONContains nested object mismatch", goent.JSONContains(attrs, map[string]any{"dims": map[string]any{"w": 3}}))
+	titles("JSONContains type mismatch", goent.JSONContains(attrs, map[string]any{"size": "3"}))
+	titles("JSONContains null", goent.JSONContains(attrs, map[string]any{"note": nil}), "guide")
+
+	unmarshalable := map[string]any{"f": func() {}}
+	if _, err = db.Document.Select().Filter(goent.JSONContains(attrs, unmarshalable)).All(); err == nil {
+		t.Error("JSONContains: expected the marshal error of the value")
+	}
+	if err = db.Document.Insert().One(&Document{Title: "bad", Attrs: unmarshalable}); err == nil {
+		t.Error("Insert: expected the marshal error of the JSON column")
+	}
+
+	stored.Meta.Pages = 13
+	if err = db.Document.Save().One(stored); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if err = db.Document.Update().Set(goent.Pair{Key: "tags", Value: []string{"archived"}},
+		goent.Pair{Key: "draft", Value: &DocumentMeta{Author: "eve"}}).ByPK(guide.Id); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	stored, err = db.Document.FindByPK(guide.Id)
+	if err != nil || !slices.Equal(stored.Tags, []string{"archived"}) || stored.Meta.Pages != 13 ||
+		stored.Draft == nil || stored.Draft.Author != "eve" {
+		t.Errorf("Update and Save: unexpected document %+v (%v)", stored, err)
+	}
+}
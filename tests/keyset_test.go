@@ -0,0 +1,79 @@
+package goent_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestKeysetPagination verifies paging forward and backward with cursors over a non unique order,
+// and that rows inserted before the cursor do not shift the next page.
+func TestKeysetPagination(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	people := []*Person{{Name: "eve"}, {Name: "bob"}, {Name: "dan"}, {Name: "bob"},
+		{Name: "ann"}, {Name: "cat"}, {Name: "dan"}}
+	if err = db.Person.Insert().All(true, people); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	names := func(rows []*Person) []string {
+		res := make([]string, len(rows))
+		for i, row := range rows {
+			res[i] = row.Name
+		}
+		return res
+	}
+	page := func(step, cursor string, before bool) *goent.Keyset[Person, Person] {
+		t.Helper()
+		query := db.Person.Select().OrderBy("name DESC")
+		if before {
+			query.Before(cursor)
+		} else {
+			query.After(cursor)
+		}
+		p, err := query.KeysetPagination(3)
+		if err != nil {
+			t.Fatalf("%s error: %v", step, err)
+		}
+		return p
+	}
+
+	first := page("First page", "", false)
+	if got := names(first.Values); !slices.Equal(got, []string{"eve", "dan", "dan"}) || first.HasPreviousPage || !first.HasNextPage {
+		t.Errorf("First page: got %v, previous %v, next %v", got, first.HasPreviousPage, first.HasNextPage)
+	}
+
+	// fay is sorted before the cursor, it does not shift the next page but is seen going back
+	if err = db.Person.Insert().One(&Person{Name: "fay"}); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	second := page("Second page", first.NextCursor, false)
+	if got := names(second.Values); !slices.Equal(got, []string{"cat", "bob", "bob"}) || !second.HasPreviousPage || !second.HasNextPage {
+		t.Errorf("Second page: got %v, previous %v, next %v", got, second.HasPreviousPage, second.HasNextPage)
+	}
+	if second.Values[1].Id > second.Values[2].Id {
+		t.Errorf("Second page: expected the primary key to order the ties, got %d before %d", second.Values[1].Id, second.Values[2].Id)
+	}
+	last := page("Last page", second.NextCursor, false)
+	if got := names(last.Values); !slices.Equal(got, []string{"ann"}) || last.HasNextPage || last.NextCursor != "" {
+		t.Errorf("Last page: got %v, next %v", got, last.HasNextPage)
+	}
+
+	back := page("Back from the last page", last.PreviousCursor, true)
+	if got := names(back.Values); !slices.Equal(got, names(second.Values)) || !back.HasPreviousPage || !back.HasNextPage {
+		t.Errorf("Back from the last page: expected %v, got %v", names(second.Values), got)
+	}
+	start := page("Back to the start", back.PreviousCursor, true)
+	if got := names(start.Values); !slices.Equal(got, []string{"eve", "dan", "dan"}) || !start.HasPreviousPage {
+		t.Errorf("Back to the start: got %v, previous %v", got, start.HasPreviousPage)
+	}
+
+	_, err = db.Person.Select().OrderBy("name").After("not a cursor").KeysetPagination(3)
+	if !errors.Is(err, model.ErrInvalidCursor) {
+		t.Errorf("Invalid cursor: expected ErrInvalidCursor, got %v", err)
+	}
+}
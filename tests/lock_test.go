@@ -0,0 +1,85 @@
+package goent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestRowLockClause verifies the rendering of the row locking clauses.
+func TestRowLockClause(t *testing.T) {
+	b := goent.GetBuilder()
+	defer goent.PutBuilder(b)
+	b.Type = model.SelectQuery
+	b.SetTableName("job")
+	b.ForShare = true
+	if sql, _ := b.Build(false); !strings.HasSuffix(sql, "FROM job FOR SHARE") {
+		t.Errorf("Expected a FOR SHARE clause, got %s", sql)
+	}
+
+	db, err := Setup()
+	if err != nil {
+		t.Skipf("Skipping test: database setup failed: %v", err)
+		return
+	}
+	b.Reset()
+	b.Type = model.SelectQuery
+	b.SetTable(db.Habitat.TableInfo)
+	b.ForUpdate = true
+	b.SetLockTables(db.Habitat.TableInfo).SetLockWait("SKIP LOCKED")
+	if sql, _ := b.Build(false); !strings.HasSuffix(sql, `FROM `+db.Habitat.TableInfo.GetFormattedName()+
+		` FOR UPDATE OF "habitat" SKIP LOCKED`) {
+		t.Errorf("Expected a FOR UPDATE OF clause with the unqualified table, got %s", sql)
+	}
+}
+
+// TestRowLocking verifies that locked rows are skipped by another transaction with SkipLocked,
+// and that a select in a transaction does not lock rows by itself. SQLite has no row locks,
+// the locking options are ignored there.
+func TestRowLocking(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	jobs := []*Category{{Name: "first"}, {Name: "second"}, {Name: "third"}}
+	if err = db.Category.Insert().All(true, jobs); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+
+	tx, err := db.NewTransaction()
+	if err != nil {
+		t.Fatalf("Begin transaction error: %v", err)
+	}
+	defer tx.Rollback()
+	locked, err := db.Category.Select().OnTransaction(tx).OrderBy("id").Take(2).LockForUpdate().SkipLocked().All()
+	if err != nil || len(locked) != 2 {
+		t.Fatalf("Lock for update: expected 2 rows, got %d (%v)", len(locked), err)
+	}
+	if db.DriverName() != "PostgreSQL" {
+		return
+	}
+
+	other, err := db.NewTransaction()
+	if err != nil {
+		t.Fatalf("Begin other transaction error: %v", err)
+	}
+	defer other.Rollback()
+	rest, err := db.Category.Select().OnTransaction(other).OrderBy("id").LockForUpdate().SkipLocked().All()
+	if err != nil || len(rest) != 1 || rest[0].Name != "third" {
+		t.Errorf("Skip locked: expected only the third row, got %v (%v)", rest, err)
+	}
+	if _, err = db.Category.Select().OnTransaction(other).LockForShare().NoWait().All(); err == nil {
+		t.Error("No wait: expected an error on the locked rows")
+	}
+	other.Rollback()
+
+	other, err = db.NewTransaction()
+	if err != nil {
+		t.Fatalf("Begin other transaction error: %v", err)
+	}
+	defer other.Rollback()
+	if all, err := db.Category.Select().OnTransaction(other).All(); err != nil || len(all) != 3 {
+		t.Errorf("Read in a transaction: expected 3 rows without waiting, got %d (%v)", len(all), err)
+	}
+}
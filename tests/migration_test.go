@@ -0,0 +1,217 @@
+package goent_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+func TestVersionedMigrations(t *testing.T) {
+	db, err := Setup()
+	if err != nil {
+		t.Skipf("Skipping test: database setup failed: %v", err)
+		return
+	}
+	ctx := context.Background()
+	defer func() {
+		_ = db.RawExecContext(ctx, "DROP TABLE IF EXISTS mig_note")
+		_ = db.RawExecContext(ctx, "DROP TABLE IF EXISTS "+goent.MigrationTable)
+	}()
+
+	migs := db.Migrations()
+	err = migs.LoadFiles(fstest.MapFS{
+		"0001_create_note.up.sql":   {Data: []byte("CREATE TABLE mig_note (id INTEGER NOT NULL PRIMARY KEY, body TEXT NOT NULL);")},
+		"0001_create_note.down.sql": {Data: []byte("DROP TABLE mig_note;")},
+		"README.md":                 {Data: []byte("not a migration")},
+	})
+	if err != nil {
+		t.Fatalf("LoadFiles failed: %v", err)
+	}
+	err = migs.Register(&goent.Migration{
+		Version: 2,
+		Name:    "seed_note",
+		UpFunc: func(ctx context.Context, tx model.Transaction) error {
+			query := model.CreateQuery("INSERT INTO mig_note (id, body) VALUES (1, 'hello')", nil)
+			return tx.ExecContext(ctx, &query)
+		},
+		DownFunc: func(ctx context.Context, tx model.Transaction) error {
+			query := model.CreateQuery("DELETE FROM mig_note WHERE id = 1", nil)
+			return tx.ExecContext(ctx, &query)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err = migs.Register(&goent.Migration{Version: 2, Name: "again"}); !errors.Is(err, model.ErrMigrationVersion) {
+		t.Errorf("Expected ErrMigrationVersion for duplicate version, got %v", err)
+	}
+
+	count, err := migs.Up(ctx)
+	if err != nil || count != 2 {
+		t.Fatalf("Up: expected 2 applied, got %d (%v)", count, err)
+	}
+	rows, err := countMigNotes(ctx, db)
+	if err != nil || rows != 1 {
+		t.Fatalf("Expected 1 seeded row, got %d (%v)", rows, err)
+	}
+	if count, err = migs.Up(ctx); err != nil || count != 0 {
+		t.Errorf("Second Up: expected nothing to apply, got %d (%v)", count, err)
+	}
+
+	if count, err = migs.Down(ctx, 1); err != nil || count != 1 {
+		t.Fatalf("Down(1): expected 1 reverted, got %d (%v)", count, err)
+	}
+	status, err := migs.Status(ctx)
+	if err != nil || len(status) != 2 {
+		t.Fatalf("Status: expected 2 entries, got %d (%v)", len(status), err)
+	}
+	if !status[0].Applied || status[0].AppliedAt == nil || status[1].Applied {
+		t.Errorf("Unexpected status after Down(1): %+v", status)
+	}
+
+	if _, err = migs.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if err = migs.Redo(ctx); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if rows, err = countMigNotes(ctx, db); err != nil || rows != 1 {
+		t.Errorf("Expected 1 row after Redo, got %d (%v)", rows, err)
+	}
+
+	if count, err = migs.Down(ctx, 5); err != nil || count != 2 {
+		t.Errorf("Down(5): expected 2 reverted, got %d (%v)", count, err)
+	}
+}
+
+func countMigNotes(ctx context.Context, db *Database) (count int64, err error) {
+	rows, err := db.RawQueryContext(ctx, "SELECT COUNT(*) FROM mig_note")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.Scan(&count)
+	}
+	return count, err
+}
+
+func TestGenerateMigration(t *testing.T) {
+	db := setupTables(t, "Exam")
+	var err error
+	ctx := context.Background()
+	if err = db.RawExecContext(ctx, "ALTER TABLE "+db.Exam.TableInfo.String()+" DROP COLUMN minimum"); err != nil {
+		t.Fatalf("Drop column failed: %v", err)
+	}
+	defer func() {
+		if err := goent.AutoMigrate(db); err != nil {
+			t.Errorf("AutoMigrate failed: %v", err)
+		}
+	}()
+
+	dir := t.TempDir()
+	path, err := goent.GenerateMigration(ctx, db, dir, "sync")
+	if err != nil {
+		t.Fatalf("GenerateMigration failed: %v", err)
+	}
+	if filepath.Base(path) != "0001_sync.up.sql" {
+		t.Fatalf("Expected 0001_sync.up.sql, got %q", path)
+	}
+	upSql, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(upSql), "minimum") {
+		t.Errorf("Expected the up file to add minimum, got %q (%v)", upSql, err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "0001_sync.down.sql")); err != nil {
+		t.Errorf("Expected down file: %v", err)
+	}
+
+	next, err := goent.WriteMigrationFiles(dir, "manual", "", "")
+	if err != nil || filepath.Base(next) != "0002_manual.up.sql" {
+		t.Errorf("Expected 0002_manual.up.sql, got %s (%v)", next, err)
+	}
+}
+
+func TestPlanMigrate(t *testing.T) {
+	db := setupTables(t, "Exam")
+	var err error
+	ctx := context.Background()
+	exam := db.Exam.TableInfo.String()
+	if err = db.RawExecContext(ctx, "ALTER TABLE "+exam+" DROP COLUMN minimum"); err != nil {
+		t.Fatalf("Drop column failed: %v", err)
+	}
+	defer func() {
+		if err := goent.AutoMigrate(db); err != nil {
+			t.Errorf("AutoMigrate failed: %v", err)
+		}
+	}()
+
+	plan, err := goent.PlanMigrateContext(ctx, db)
+	if err != nil {
+		t.Fatalf("PlanMigrate failed: %v", err)
+	}
+	if plan.IsEmpty() || !strings.Contains(plan.Sql, "minimum") {
+		t.Fatalf("Expected SQL adding minimum, got %q", plan.Sql)
+	}
+	found := false
+	for _, c := range plan.AddedColumns {
+		if strings.HasSuffix(c.Table, "exam") && c.Column == "minimum" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected exam.minimum in added columns, got %+v", plan.AddedColumns)
+	}
+	if !strings.Contains(plan.String(), "+ column ") {
+		t.Errorf("Expected human readable diff, got %q", plan.String())
+	}
+
+	cols, err := goent.NewSchemaOps(db.DB).GetColumns(ctx, "exam")
+	if err != nil {
+		t.Fatalf("GetColumns failed: %v", err)
+	}
+	for _, c := range cols {
+		if c.Name == "minimum" {
+			t.Error("Plan mode must not execute the migration")
+		}
+	}
+}
+
+// TestMigrateRebuildNewColumn verifies that a SQLite table rebuild copies the existing rows
+// with the zero value in a new NOT NULL column without a default.
+func TestMigrateRebuildNewColumn(t *testing.T) {
+	db := setupTables(t, "Exam")
+	var err error
+	if db.DriverName() == "PostgreSQL" {
+		t.Skip("Skipping test: only SQLite rebuilds tables")
+	}
+
+	if err = db.Exam.Insert().One(&Exam{Id: 1, Score: 7.5, Minimum: 5}); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	ctx := context.Background()
+	if err = db.RawExecContext(ctx, "ALTER TABLE "+db.Exam.TableInfo.String()+" DROP COLUMN minimum"); err != nil {
+		t.Fatalf("Drop column failed: %v", err)
+	}
+	plan, err := goent.PlanMigrateContext(ctx, db)
+	if err != nil {
+		t.Fatalf("PlanMigrate failed: %v", err)
+	}
+	if !slices.ContainsFunc(plan.RebuiltTables, func(table string) bool { return strings.HasSuffix(table, "exam") }) {
+		t.Errorf("Expected a rebuild of exam, got %+v", plan.RebuiltTables)
+	}
+	if err = goent.AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	exam, err := db.Exam.FindByPK(1)
+	if err != nil || exam.Score != 7.5 || exam.Minimum != 0 {
+		t.Errorf("Expected the row with a zero minimum, got %+v (%v)", exam, err)
+	}
+}
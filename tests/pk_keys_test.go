@@ -0,0 +1,80 @@
+package goent_test
+
+import (
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/google/uuid"
+)
+
+// TestUUIDPrimaryKey verifies the by-PK operations, the by-ID two-phase
+// operations and eager loading on a table keyed by a UUID.
+func TestUUIDPrimaryKey(t *testing.T) {
+	db := setupTables(t, "Animal", "Habitat")
+	var err error
+
+	weather := &Weather{Name: "Foggy"}
+	if err = db.Weather.Insert().One(weather); err != nil {
+		t.Fatalf("Insert Weather error: %v", err)
+	}
+	habitat := &Habitat{Id: uuid.New(), Name: "Marsh", WeatherId: weather.Id}
+	if err = db.Habitat.Insert().One(habitat); err != nil {
+		t.Fatalf("Insert Habitat error: %v", err)
+	}
+	animals := []*Animal{
+		{Name: "Frog", HabitatId: &habitat.Id},
+		{Name: "Heron", HabitatId: &habitat.Id},
+	}
+	if err = db.Animal.Insert().All(true, animals); err != nil {
+		t.Fatalf("Insert Animals error: %v", err)
+	}
+
+	found, err := db.Habitat.FindByPK(habitat.Id)
+	if err != nil || found.Name != "Marsh" {
+		t.Fatalf("FindByPK: expected Marsh, got %+v (%v)", found, err)
+	}
+	if err = db.Habitat.Update().Set(goent.Pair{Key: "name", Value: "Swamp"}).ByPK(habitat.Id); err != nil {
+		t.Fatalf("Update ByPK error: %v", err)
+	}
+	if found, err = db.Habitat.Select().ByPK(habitat.Id); err != nil || found.Name != "Swamp" {
+		t.Fatalf("Select ByPK: expected Swamp, got %+v (%v)", found, err)
+	}
+
+	byKey, err := goent.MapBy[uuid.UUID](db.Habitat.Select(), "id")
+	if err != nil || byKey[habitat.Id] == nil {
+		t.Fatalf("MapBy: expected habitat %s, got %v (%v)", habitat.Id, byKey, err)
+	}
+
+	habitats, err := db.Habitat.Select().With("Animals").All()
+	if err != nil || len(habitats) != 1 {
+		t.Fatalf("With Animals: expected 1 habitat, got %d (%v)", len(habitats), err)
+	}
+	if len(habitats[0].Animals) != 2 {
+		t.Errorf("With Animals: expected 2 animals, got %d", len(habitats[0].Animals))
+	}
+
+	rows := []*Habitat{{Id: habitat.Id}}
+	byHabitat, err := goent.QueryOne2ManyBy[uuid.UUID](goent.GetForeign(db.Habitat, db.Animal), db.Habitat, db.Animal, rows)
+	if err != nil || len(byHabitat[habitat.Id]) != 2 || len(rows[0].Animals) != 2 {
+		t.Errorf("QueryOne2ManyBy: expected 2 animals of %s, got %v (%v)", habitat.Id, byHabitat, err)
+	}
+	weathers := []*Weather{{Id: weather.Id}}
+	byWeather, err := goent.QueryOne2Many(goent.GetForeign(db.Weather, db.Habitat), db.Weather, db.Habitat, weathers)
+	if err != nil || len(byWeather[int64(weather.Id)]) != 1 || len(weathers[0].Habitats) != 1 {
+		t.Errorf("QueryOne2Many: expected 1 habitat of %d, got %v (%v)", weather.Id, byWeather, err)
+	}
+
+	keys, err := db.Habitat.Filter(goent.Equals(db.Habitat.Field("name"), "Swamp")).
+		UpdateByID().Set(goent.Pair{Key: "name_weather", Value: "Swamp Foggy"}).ExecKeys()
+	if err != nil || len(keys) != 1 || keys[0] != habitat.Id {
+		t.Fatalf("UpdateByID ExecKeys: expected [%s], got %v (%v)", habitat.Id, keys, err)
+	}
+
+	db.Animal.Delete().Exec()
+	if err = db.Habitat.Delete().ByPK(habitat.Id); err != nil {
+		t.Fatalf("Delete ByPK error: %v", err)
+	}
+	if _, err = db.Habitat.FindByPK(habitat.Id); err == nil {
+		t.Error("Expected an error finding a deleted habitat")
+	}
+}
@@ -0,0 +1,90 @@
+package goent_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestSoftDelete verifies that deletes on a goe:"soft_delete" table only mark the rows,
+// that queries skip them by default, and the trashed, restore and force delete variants.
+func TestSoftDelete(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	person := &Person{Name: "Grace"}
+	if err = db.Person.Insert().One(person); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	notes := []*PersonNote{
+		{PersonId: person.Id, Body: "first"},
+		{PersonId: person.Id, Body: "second"},
+		{PersonId: person.Id, Body: "third"},
+		{PersonId: person.Id, Body: "fourth"},
+	}
+	if err = db.PersonNote.Insert().All(true, notes); err != nil {
+		t.Fatalf("Insert PersonNote error: %v", err)
+	}
+	body := db.PersonNote.Field("body")
+
+	if err = db.PersonNote.Filter(goent.Equals(body, "first")).Delete().Exec(); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if err = db.PersonNote.Delete().ByPK(notes[1].Id); err != nil {
+		t.Fatalf("Delete ByPK error: %v", err)
+	}
+	ids, err := db.PersonNote.Filter(goent.Equals(body, "third")).DeleteByID().Exec()
+	if err != nil || len(ids) != 1 || ids[0] != int64(notes[2].Id) {
+		t.Fatalf("DeleteByID: expected [%d], got %v (%v)", notes[2].Id, ids, err)
+	}
+
+	if count, _ := db.PersonNote.Count("id"); count != 1 {
+		t.Errorf("Count: expected 1 remaining note, got %d", count)
+	}
+	if rows, _ := db.PersonNote.Select().All(); len(rows) != 1 || rows[0].Body != "fourth" {
+		t.Errorf("Select: expected only the fourth note, got %+v", rows)
+	}
+	if _, err = db.PersonNote.FindByPK(notes[0].Id); err == nil {
+		t.Error("FindByPK: expected an error for a soft deleted note")
+	}
+	if found, err := db.PersonNote.Select().WithTrashed().ByPK(notes[0].Id); err != nil || found.DeletedAt == nil {
+		t.Errorf("WithTrashed ByPK: expected a deleted note, got %+v (%v)", found, err)
+	}
+	if rows, _ := db.PersonNote.Select().Unscoped().All(); len(rows) != 4 {
+		t.Errorf("Unscoped: expected 4 notes, got %d", len(rows))
+	}
+	if count, _ := db.PersonNote.Filter().OnlyTrashed().Count("id"); count != 3 {
+		t.Errorf("OnlyTrashed Count: expected 3 notes, got %d", count)
+	}
+	page, err := db.PersonNote.Select().Pagination(1, 10)
+	if err != nil || page.TotalValues != 1 {
+		t.Errorf("Pagination: expected 1 note, got %+v (%v)", page, err)
+	}
+
+	persons, err := db.Person.Select().With("Notes").All()
+	if err != nil || len(persons) != 1 {
+		t.Fatalf("With Notes: expected 1 person, got %d (%v)", len(persons), err)
+	}
+	if len(persons[0].Notes) != 1 {
+		t.Errorf("With Notes: expected 1 note, got %d", len(persons[0].Notes))
+	}
+
+	if err = db.PersonNote.Restore(goent.Equals(body, "first")); err != nil {
+		t.Fatalf("Restore error: %v", err)
+	}
+	if count, _ := db.PersonNote.Count("id"); count != 2 {
+		t.Errorf("Restore: expected 2 notes, got %d", count)
+	}
+	if err = db.Person.Restore(); !errors.Is(err, model.ErrNoSoftDelete) {
+		t.Errorf("Expected ErrNoSoftDelete, got %v", err)
+	}
+
+	if err = db.PersonNote.Filter().OnlyTrashed().Delete().ForceDelete(); err != nil {
+		t.Fatalf("ForceDelete trashed error: %v", err)
+	}
+	if rows, _ := db.PersonNote.Select().Unscoped().All(); len(rows) != 2 {
+		t.Errorf("ForceDelete: expected 2 notes left, got %d", len(rows))
+	}
+}
@@ -0,0 +1,89 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/google/uuid"
+)
+
+// TestStream verifies that Stream yields all the rows in order and in batches, follows the filter,
+// the order, Skip and Take, and stops when the loop breaks.
+func TestStream(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	rows := []*Category{{Name: "a", ParentId: 1}, {Name: "b", ParentId: 2}, {Name: "c", ParentId: 1},
+		{Name: "d", ParentId: 2}, {Name: "e", ParentId: 1}}
+	if err = db.Category.Insert().All(true, rows); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	stream := func(step string, s *goent.StateSelect[Category, Category], size int, want ...string) {
+		t.Helper()
+		var got []string
+		for batch, err := range s.Stream(size) {
+			if err != nil {
+				t.Fatalf("%s error: %v", step, err)
+			}
+			if len(batch) == 0 || len(batch) > size {
+				t.Errorf("%s: unexpected batch size %d", step, len(batch))
+			}
+			for _, row := range batch {
+				got = append(got, row.Name)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+	stream("Stream", db.Category.Select(), 2, "a", "b", "c", "d", "e")
+	stream("Stream one batch", db.Category.Select(), 10, "a", "b", "c", "d", "e")
+	stream("Stream filter", db.Category.Select().Filter(goent.Equals(db.Category.Field("parent_id"), 1)), 2,
+		"a", "c", "e")
+	stream("Stream order", db.Category.Select().OrderBy("parent_id DESC"), 2, "b", "d", "a", "c", "e")
+	stream("Stream skip and take", db.Category.Select().Skip(1).Take(3), 2, "b", "c", "d")
+
+	batches := 0
+	for batch, err := range db.Category.Select().Stream(2) {
+		if err != nil || len(batch) != 2 {
+			t.Fatalf("Stream break: unexpected batch %v (%v)", batch, err)
+		}
+		if batches++; batches == 2 {
+			break
+		}
+	}
+	if batches != 2 {
+		t.Errorf("Stream break: expected 2 batches, got %d", batches)
+	}
+}
+
+// TestStreamWith verifies that Stream loads the relations of With for each batch.
+func TestStreamWith(t *testing.T) {
+	db := setupTables(t, "Habitat", "Weather")
+	var err error
+
+	weathers := []*Weather{{Name: "sunny"}, {Name: "rainy"}, {Name: "windy"}}
+	if err = db.Weather.Insert().All(true, weathers); err != nil {
+		t.Fatalf("Insert Weather error: %v", err)
+	}
+	habitats := []*Habitat{{Id: uuid.New(), Name: "desert", WeatherId: weathers[0].Id},
+		{Id: uuid.New(), Name: "forest", WeatherId: weathers[1].Id},
+		{Id: uuid.New(), Name: "swamp", WeatherId: weathers[1].Id}}
+	if err = db.Habitat.Insert().All(false, habitats); err != nil {
+		t.Fatalf("Insert Habitat error: %v", err)
+	}
+
+	got := make(map[string]int)
+	for batch, err := range db.Weather.Select().With("Habitats").Stream(2) {
+		if err != nil {
+			t.Fatalf("Stream error: %v", err)
+		}
+		for _, w := range batch {
+			got[w.Name] = len(w.Habitats)
+		}
+	}
+	if len(got) != 3 || got["sunny"] != 1 || got["rainy"] != 2 || got["windy"] != 0 {
+		t.Errorf("Stream With: unexpected habitats per weather %v", got)
+	}
+}
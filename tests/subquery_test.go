@@ -0,0 +1,69 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestSubqueryConditions verifies IN, EXISTS and scalar comparisons against a subquery,
+// with correlated references and placeholders numbered across the outer query.
+func TestSubqueryConditions(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	people := []*Person{{Name: "Ada"}, {Name: "Alan"}, {Name: "Grace"}}
+	if err = db.Person.Insert().All(true, people); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	notes := []*PersonNote{
+		{PersonId: people[0].Id, Body: "engine"},
+		{PersonId: people[1].Id, Body: "machine"},
+		{PersonId: people[1].Id, Body: "engine"},
+	}
+	if err = db.PersonNote.Insert().All(true, notes); err != nil {
+		t.Fatalf("Insert PersonNote error: %v", err)
+	}
+	names := func(step string, cond goent.Condition, want ...string) {
+		t.Helper()
+		rows, err := db.Person.Filter(cond).Select().OrderBy("name").All()
+		if err != nil {
+			t.Fatalf("%s: select error: %v", step, err)
+		}
+		got := make([]string, len(rows))
+		for i, row := range rows {
+			got[i] = row.Name
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", step, want, got)
+		}
+	}
+
+	engines := db.PersonNote.Select("person_id").Filter(goent.Equals(db.PersonNote.Field("body"), "engine"))
+	names("InSelect", goent.InSelect(db.Person.Field("id"), engines), "Ada", "Alan")
+	names("NotInSelect", goent.NotInSelect(db.Person.Field("id"), engines), "Grace")
+
+	names("Placeholder numbering", goent.And(
+		goent.Like(db.Person.Field("name"), "A%"),
+		goent.InSelect(db.Person.Field("id"), db.PersonNote.Select("person_id").
+			Filter(goent.Equals(db.PersonNote.Field("body"), "machine"))),
+		goent.NotEquals(db.Person.Field("name"), "Ada"),
+	), "Alan")
+
+	correlated := db.PersonNote.Select().Filter(
+		goent.EqualsField(db.PersonNote.Field("person_id"), db.Person.Field("id")),
+		goent.Equals(db.PersonNote.Field("body"), "machine"),
+	)
+	names("Exists", goent.Exists(correlated), "Alan")
+	names("NotExists", goent.NotExists(correlated), "Ada", "Grace")
+
+	maxNote := db.PersonNote.Select(db.PersonNote.Field("person_id").Func("MAX(%s)", "")).
+		Filter(goent.Equals(db.PersonNote.Field("body"), "engine"))
+	names("Scalar", goent.Equals(db.Person.Field("id"), maxNote), "Alan")
+	names("Scalar Less", goent.Less(db.Person.Field("id"), maxNote), "Ada")
+
+	if count, err := db.Person.Filter(goent.InSelect(db.Person.Field("id"), engines)).Count("id"); err != nil || count != 2 {
+		t.Errorf("Count with a subquery: expected 2, got %d (%v)", count, err)
+	}
+}
@@ -0,0 +1,85 @@
+package goent_test
+
+import (
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestUpdateExpressions verifies the increments, expressions, column copies and CASE
+// assignments of UPDATE, through StateUpdateByID and a JOIN update.
+func TestUpdateExpressions(t *testing.T) {
+	db := setupTables(t, "Category", "PersonNote", "Person")
+	var err error
+
+	rows := []*Category{{Name: "a", ParentId: 1}, {Name: "b", ParentId: 5}, {Name: "c", ParentId: 20}}
+	if err = db.Category.Insert().All(true, rows); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	parent := func(step string, row *Category, want int) {
+		t.Helper()
+		got, err := db.Category.FindByPK(row.Id)
+		if err != nil || got.ParentId != want {
+			t.Errorf("%s: expected parent_id %d of %q, got %+v (%v)", step, want, row.Name, got, err)
+		}
+	}
+
+	if err = db.Category.Update().Incr("parent_id", -1).ByPK(rows[0].Id); err != nil {
+		t.Fatalf("Incr error: %v", err)
+	}
+	parent("Incr", rows[0], 0)
+
+	name := db.Category.Field("name")
+	if err = db.Category.Update().SetExpr("parent_id", "parent_id * ? + ?", 3, 1).
+		Filter(goent.Equals(name, "b")).Exec(); err != nil {
+		t.Fatalf("SetExpr error: %v", err)
+	}
+	parent("SetExpr", rows[1], 16)
+
+	if err = db.Category.Update().SetField("parent_id", db.Category.Field("id")).
+		Filter(goent.Equals(name, "c")).Exec(); err != nil {
+		t.Fatalf("SetField error: %v", err)
+	}
+	parent("SetField", rows[2], rows[2].Id)
+
+	level := goent.Case().
+		When(goent.GreaterEquals(db.Category.Field("parent_id"), 16), "high").
+		When(goent.Greater(db.Category.Field("parent_id"), 0), "low").
+		Else("none")
+	if err = db.Category.Update().SetCase("name", level).Exec(); err != nil {
+		t.Fatalf("SetCase error: %v", err)
+	}
+	names, err := db.Category.Select().OrderBy("id").All()
+	if err != nil || len(names) != 3 || names[0].Name != "none" || names[1].Name != "high" ||
+		(names[2].Name != "high") != (rows[2].Id < 16) {
+		t.Errorf("SetCase: unexpected rows %+v (%v)", names, err)
+	}
+
+	ids, err := db.Category.Filter(goent.In(db.Category.Field("id"), []int{rows[0].Id, rows[1].Id})).
+		UpdateByID().Incr("parent_id", 10).Exec()
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("UpdateByID Incr: expected 2 ids, got %v (%v)", ids, err)
+	}
+	parent("UpdateByID Incr", rows[0], 10)
+	parent("UpdateByID Incr", rows[1], 26)
+
+	person := &Person{Name: "Grace"}
+	if err = db.Person.Insert().One(person); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	note := &PersonNote{PersonId: person.Id, Body: "draft"}
+	if err = db.PersonNote.Insert().One(note); err != nil {
+		t.Fatalf("Insert PersonNote error: %v", err)
+	}
+	on := goent.EqualsField(db.PersonNote.Field("person_id"), db.Person.Field("id"))
+	err = db.PersonNote.Update().Join(model.InnerJoin, db.Person.TableInfo, on).
+		SetField("body", db.Person.Field("name")).Incr("version", 1).Exec()
+	if err != nil {
+		t.Fatalf("JOIN update error: %v", err)
+	}
+	stored, err := db.PersonNote.FindByPK(note.Id)
+	if err != nil || stored.Body != "Grace" || stored.Version != note.Version+1 {
+		t.Errorf("JOIN update: expected the person name and the next version, got %+v (%v)", stored, err)
+	}
+}
@@ -0,0 +1,103 @@
+package goent_test
+
+import (
+	"testing"
+
+	"github.com/azhai/gobus"
+	"github.com/azhai/goent"
+)
+
+// TestUpsert verifies that OnConflict turns Insert().One() and Insert().All() into upserts
+// on a unique column, and that the primary keys of inserted and updated rows are filled.
+func TestUpsert(t *testing.T) {
+	db := setupTables(t, "UserRole", "User")
+	var err error
+
+	ada := &User{Name: "Ada", Email: "ada@example.com"}
+	if err = db.User.Insert().One(ada); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+
+	renamed := &User{Name: "Ada Lovelace", Email: "ada@example.com"}
+	if err = db.User.Insert().OnConflict("email").DoUpdate("name").One(renamed); err != nil {
+		t.Fatalf("Upsert One error: %v", err)
+	}
+	if renamed.Id != ada.Id {
+		t.Errorf("Upsert One: expected the id %d of the existing row, got %d", ada.Id, renamed.Id)
+	}
+	if stored, _ := db.User.FindByPK(ada.Id); stored == nil || stored.Name != "Ada Lovelace" {
+		t.Errorf("Upsert One: expected the updated name, got %+v", stored)
+	}
+
+	batch := []*User{
+		{Name: "Ignored", Email: "ada@example.com"},
+		{Name: "Grace", Email: "grace@example.com"},
+	}
+	if err = db.User.Insert().OnConflict("email").DoNothing().All(true, batch); err != nil {
+		t.Fatalf("Upsert All DoNothing error: %v", err)
+	}
+	if batch[0].Id != 0 || batch[1].Id == 0 {
+		t.Errorf("DoNothing: expected only the new row to get an id, got %d / %d", batch[0].Id, batch[1].Id)
+	}
+	if stored, _ := db.User.FindByPK(ada.Id); stored == nil || stored.Name != "Ada Lovelace" {
+		t.Errorf("DoNothing: expected the existing row unchanged, got %+v", stored)
+	}
+
+	batch = []*User{
+		{Name: "Ada", Email: "ada@example.com"},
+		{Name: "Grace", Email: "grace@example.com"},
+		{Name: "Barbara", Email: "barbara@example.com"},
+	}
+	err = db.User.Insert().OnConflict(db.User.Field("email")).
+		DoUpdateExpr("name", "EXCLUDED.name || '!'").All(true, batch)
+	if err != nil {
+		t.Fatalf("Upsert All DoUpdateExpr error: %v", err)
+	}
+	for _, user := range batch {
+		stored, _ := db.User.FindByPK(user.Id)
+		if stored == nil || stored.Email != user.Email {
+			t.Errorf("DoUpdateExpr: expected the id of %s to be filled, got %d", user.Email, user.Id)
+		}
+	}
+	if batch[0].Id != ada.Id {
+		t.Errorf("DoUpdateExpr: expected the existing id %d, got %d", ada.Id, batch[0].Id)
+	}
+	if stored, _ := db.User.FindByPK(ada.Id); stored == nil || stored.Name != "Ada!" {
+		t.Errorf("DoUpdateExpr: expected the expression to set the name, got %+v", stored)
+	}
+	if count, _ := db.User.Count("id"); count != 3 {
+		t.Errorf("Expected 3 users, got %d", count)
+	}
+}
+
+// TestUpsertOneDoNothing verifies that Insert().One() publishes no insert event
+// when DoNothing skips the row, and reports the row count of an upsert.
+func TestUpsertOneDoNothing(t *testing.T) {
+	db := setupTables(t, "UserRole", "User")
+	var err error
+	bus := gobus.NewEventBus(64)
+	capture := newEventCapture()
+	if err = bus.Subscribe(goent.EventTopicInsertOne, gobus.Fanout, "test-upsert", capture.handler); err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	db.Watching(bus, db.User.TableInfo)
+	t.Cleanup(func() { db.Watching(nil) })
+
+	ada := &User{Name: "Ada", Email: "ada@example.com"}
+	if err = db.User.Insert().OnConflict("email").DoNothing().One(ada); err != nil {
+		t.Fatalf("Upsert One error: %v", err)
+	}
+	skipped := &User{Name: "Ignored", Email: "ada@example.com"}
+	if err = db.User.Insert().OnConflict("email").DoNothing().One(skipped); err != nil {
+		t.Fatalf("Upsert One DoNothing error: %v", err)
+	}
+	if skipped.Id != 0 {
+		t.Errorf("DoNothing: expected no id for the skipped row, got %d", skipped.Id)
+	}
+	if capture.len() != 1 {
+		t.Fatalf("Expected 1 insert event, got %d", capture.len())
+	}
+	if affecteds := capture.get(0).Data["affecteds"]; affecteds != int64(1) {
+		t.Errorf("Expected 1 affected row in the event, got %v", affecteds)
+	}
+}
@@ -0,0 +1,79 @@
+package goent_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azhai/goent"
+	"github.com/azhai/goent/model"
+)
+
+// TestOptimisticLock verifies that saves and updates by primary key on a table with a
+// goe:"version" column increment the version and reject stale writes, that updates by
+// primary key need the version, and that set-based updates increment it too.
+func TestOptimisticLock(t *testing.T) {
+	db := setupTables(t, "PersonNote", "Person")
+	var err error
+
+	person := &Person{Name: "Barbara"}
+	if err = db.Person.Insert().One(person); err != nil {
+		t.Fatalf("Insert Person error: %v", err)
+	}
+	note := &PersonNote{PersonId: person.Id, Body: "draft", Version: 1}
+	if err = db.PersonNote.Insert().One(note); err != nil {
+		t.Fatalf("Insert PersonNote error: %v", err)
+	}
+
+	mine, _ := db.PersonNote.FindByPK(note.Id)
+	theirs, _ := db.PersonNote.FindByPK(note.Id)
+	if mine == nil || theirs == nil {
+		t.Fatal("FindByPK: expected the note")
+	}
+	mine.Body = "mine"
+	if err = db.PersonNote.Save().One(mine); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if mine.Version != 2 {
+		t.Errorf("Save: expected version 2, got %d", mine.Version)
+	}
+	theirs.Body = "theirs"
+	err = db.PersonNote.Save().One(theirs)
+	var stale *model.StaleObjectError
+	if !errors.Is(err, model.ErrStaleObject) || !errors.As(err, &stale) || stale.Version != 1 {
+		t.Fatalf("Save: expected a stale object error for version 1, got %v", err)
+	}
+
+	err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "stale"},
+		goent.Pair{Key: "version", Value: 1}).ByPK(note.Id)
+	if !errors.Is(err, model.ErrStaleObject) {
+		t.Errorf("Update ByPK: expected ErrStaleObject, got %v", err)
+	}
+	err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "fresh"},
+		goent.Pair{Key: "version", Value: mine.Version}).ByPK(note.Id)
+	if err != nil {
+		t.Fatalf("Update ByPK error: %v", err)
+	}
+	stored, _ := db.PersonNote.FindByPK(note.Id)
+	if stored == nil || stored.Body != "fresh" || stored.Version != 3 {
+		t.Errorf("Expected body fresh with version 3, got %+v", stored)
+	}
+
+	err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "blind"}).ByPK(note.Id)
+	if !errors.Is(err, model.ErrMissingVersion) {
+		t.Errorf("Update ByPK without version: expected ErrMissingVersion, got %v", err)
+	}
+	err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "all"}).
+		Filter(goent.Equals(db.PersonNote.Field("person_id"), person.Id)).Exec()
+	if err != nil {
+		t.Fatalf("Update Exec error: %v", err)
+	}
+	stored, _ = db.PersonNote.FindByPK(note.Id)
+	if stored == nil || stored.Body != "all" || stored.Version != 4 {
+		t.Errorf("Expected body all with version 4, got %+v", stored)
+	}
+	err = db.PersonNote.Update().Set(goent.Pair{Key: "body", Value: "late"},
+		goent.Pair{Key: "version", Value: 3}).ByPK(note.Id)
+	if !errors.Is(err, model.ErrStaleObject) {
+		t.Errorf("Update ByPK after Exec: expected ErrStaleObject, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package goent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azhai/goent"
+)
+
+// TestWindowFunctions verifies window functions selected into DTOs and ResultFunc,
+// and used from an outer query to keep the top rows of each group.
+func TestWindowFunctions(t *testing.T) {
+	db := setupTables(t, "Category")
+	var err error
+
+	books := &Category{Name: "books"}
+	music := &Category{Name: "music"}
+	if err = db.Category.Insert().All(true, []*Category{books, music}); err != nil {
+		t.Fatalf("Insert roots error: %v", err)
+	}
+	children := []*Category{
+		{Name: "science", ParentId: books.Id},
+		{Name: "art", ParentId: books.Id},
+		{Name: "fiction", ParentId: books.Id},
+		{Name: "jazz", ParentId: music.Id},
+	}
+	if err = db.Category.Insert().All(true, children); err != nil {
+		t.Fatalf("Insert children error: %v", err)
+	}
+	parent, name := db.Category.Field("parent_id"), db.Category.Field("name")
+
+	type position struct {
+		Name     string
+		ParentId int
+		Pos      int64
+	}
+	positions, err := goent.SelectAs[position](db.Category, "name", "parent_id",
+		goent.RowNumber().Over(goent.PartitionBy(parent).OrderBy(name))).
+		Filter(goent.Greater(parent, 0)).OrderBy("parent_id", "name").All()
+	if err != nil {
+		t.Fatalf("Row number error: %v", err)
+	}
+	want := []position{{"art", books.Id, 1}, {"fiction", books.Id, 2}, {"science", books.Id, 3}, {"jazz", music.Id, 1}}
+	if len(positions) != len(want) {
+		t.Fatalf("Row number: expected %v, got %d rows", want, len(positions))
+	}
+	for i, row := range positions {
+		if *row != want[i] {
+			t.Errorf("Row number %d: expected %+v, got %+v", i, want[i], *row)
+		}
+	}
+
+	window := goent.OrderedBy(db.Category.Field("id")).Rows(goent.UnboundedPreceding, goent.CurrentRow)
+	running, err := goent.FetchArrayResult(goent.SelectAs[goent.ResultLong](db.Category,
+		db.Category.Field("id").Func("COUNT(%s)", "").Over(window)).OrderBy("id"))
+	if err != nil || !slices.Equal(running, []int64{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Running count: expected 1 to 6, got %v (%v)", running, err)
+	}
+
+	type neighbour struct {
+		Name     string
+		Previous *string
+	}
+	lags, err := goent.SelectAs[neighbour](db.Category, "name",
+		goent.Lag(name, 1).Over(goent.PartitionBy(parent).OrderByDesc(name))).
+		Filter(goent.Equals(parent, books.Id)).OrderBy("name").All()
+	if err != nil || len(lags) != 3 {
+		t.Fatalf("Lag: expected 3 rows, got %d (%v)", len(lags), err)
+	}
+	if lags[0].Previous == nil || *lags[0].Previous != "fiction" || lags[2].Previous != nil {
+		t.Errorf("Lag: expected art after fiction and science first, got %+v %+v", *lags[0], *lags[2])
+	}
+
+	ranked := goent.NewCTE("ranked", db.Category.Select("id", "name", "parent_id",
+		goent.RowNumber().Over(goent.PartitionBy(parent).OrderBy(name)).As("pos")))
+	top, err := db.Category.Select().FromCTE(ranked).
+		Filter(goent.Greater(parent, 0)).Where("pos <= ?", 2).OrderBy("name").All()
+	if err != nil {
+		t.Fatalf("Top per group error: %v", err)
+	}
+	got := make([]string, len(top))
+	for i, row := range top {
+		got[i] = row.Name
+	}
+	if !slices.Equal(got, []string{"art", "fiction", "jazz"}) {
+		t.Errorf("Top per group: expected [art fiction jazz], got %v", got)
+	}
+}
@@ -13,7 +13,10 @@ type StateUpdate[T any] struct {
 	others      []*Table[T] // Other tables involved in JOIN operations
 	skipEvent   bool        // Internal: skip event publishing (used by StateUpdateByID)
 	eventTopic  string      // Event topic to publish (default: ent:update; ByPK uses ent:update-bypk)
-	eventIDs    []int64     // Event IDs to publish (default: nil; ByPK sets [id])
+	eventKeys   []any       // Event keys to publish (default: nil; ByPK sets [id])
+	skipHooks   bool        // Internal: skip the model hooks (the Save fast path)
+	locked      bool        // The update is guarded by the version column (ByPK and ByKeys)
+	version     int64       // The version read by the caller when locked
 	*StateWhere             // Embedded StateWhere for WHERE clause construction
 }
 
@@ -25,17 +28,73 @@ type StateUpdate[T any] struct {
 //	change := Pair{Key:"name", Value:"John"}
 //	err := db.User.Where("id = ?", 1).Update().Set(change).Exec()
 func (s *StateUpdate[T]) Exec() error {
+	if s.skipHooks {
+		_, err := s.exec()
+		return err
+	}
+	stmt := hookStatement[T](s.table.TableInfo, s.builder.core.Where, s.builder.Changes,
+		hookBeforeUpdate, hookAfterUpdate)
+	if err := runStatementHook[T](s.ctx, s.conn, hookBeforeUpdate, stmt); err != nil {
+		PutBuilder(s.builder)
+		return err
+	}
+	affected, err := s.exec()
+	if err != nil || stmt == nil {
+		return err
+	}
+	stmt.Affected = affected
+	return runStatementHook[T](s.ctx, s.conn, hookAfterUpdate, stmt)
+}
+
+// execRow executes the UPDATE of a single row matching the key condition.
+// When the model has the row hooks, the row is loaded before the update for BeforeUpdate
+// and again after it for AfterUpdate.
+func (s *StateUpdate[T]) execRow(key Condition) error {
+	if s.skipHooks {
+		_, err := s.exec()
+		return err
+	}
+	hctx := hookContext(s.ctx, s.conn)
+	if hasHook[T](hookBeforeUpdate) {
+		row, err := s.table.hookRow(s.ctx, s.conn, key, true)
+		if err == nil && row != nil {
+			err = runHook(hctx, hookBeforeUpdate, row)
+		}
+		if err != nil {
+			PutBuilder(s.builder)
+			return err
+		}
+	}
+	affected, err := s.exec()
+	if err != nil || affected == 0 || !hasHook[T](hookAfterUpdate) {
+		return err
+	}
+	row, err := s.table.hookRow(s.ctx, s.conn, key, true)
+	if err != nil || row == nil {
+		return err
+	}
+	return runHook(hctx, hookAfterUpdate, row)
+}
+
+// exec builds and runs the UPDATE statement, then publishes its event.
+// It returns the number of affected rows.
+func (s *StateUpdate[T]) exec() (int64, error) {
 	defer PutBuilder(s.builder)
 	s.builder.SetTable(s.table.TableInfo)
+	s.table.touchChanges(s.builder.Changes, false)
+	s.table.touchVersion(s.builder.Changes)
 	sql, args := s.builder.Build(true)
 	if sql == "" {
-		return fmt.Errorf("goent: StateUpdate.Exec built empty SQL (Type=%d, Changes=%d, Where=%v, args=%v)",
+		return 0, fmt.Errorf("goent: StateUpdate.Exec built empty SQL (Type=%d, Changes=%d, Where=%v, args=%v)",
 			s.builder.Type, len(s.builder.Changes), !s.builder.core.Where.IsEmpty(), args)
 	}
 	qr := model.CreateQuery(sql, args)
 	conn, cfg := s.Prepare(s.table.TableInfo)
 	if err := qr.WrapExec(s.ctx, conn, cfg); err != nil {
-		return err
+		return 0, err
+	}
+	if s.locked && qr.RowsAffected == 0 {
+		return 0, s.table.staleError(s.version)
 	}
 	// Skip event for JOIN updates, subquery updates, and internal byid calls
 	if !s.skipEvent && !s.builder.IsJoinQuery() && !s.builder.core.Where.IsEmpty() {
@@ -44,16 +103,40 @@ func (s *StateUpdate[T]) Exec() error {
 		if topic == "" {
 			topic = EventTopicUpdate
 		}
-		publishEvent(info.db.bus, info, conn, topic,
-			s.builder.core.Where.Template, s.eventIDs, changesToMap(s.builder.Changes), qr.RowsAffected)
+		publishKeysEvent(info.db.bus, info, conn, topic,
+			s.builder.core.Where.Template, s.eventKeys, changesToMap(s.builder.Changes), qr.RowsAffected)
 	}
+	return qr.RowsAffected, nil
+}
+
+// guardVersion guards the update with the version column of a versioned table.
+// It returns model.ErrMissingVersion when the changes do not contain the version read before.
+func (s *StateUpdate[T]) guardVersion() error {
+	if !s.table.HasVersion() {
+		return nil
+	}
+	cond, current, ok := s.table.lockVersion(s.builder.Changes)
+	if !ok {
+		return model.ErrMissingVersion
+	}
+	s.builder.core.Where = And(s.builder.core.Where, cond)
+	s.locked, s.version = true, current
 	return nil
 }
 
 // ByPK updates a single row by primary key.
 // This is an optimized path for simple primary key updates.
-// Only works for tables with a single primary key column.
-func (s *StateUpdate[T]) ByPK(id int64) error {
+// Only works for tables with a single primary key column, the key can be of any type.
+//
+// For a table with a goe:"version" column, the version read before must be set with the changes,
+// the update checks it and increments it, and returns model.ErrStaleObject when the row
+// was changed in between, or model.ErrMissingVersion when the version is not set.
+//
+// Example:
+//
+//	err := db.Doc.Update().Set(goent.Pair{Key: "body", Value: body},
+//	    goent.Pair{Key: "version", Value: doc.Version}).ByPK(doc.ID)
+func (s *StateUpdate[T]) ByPK(id any) error {
 	if len(s.builder.Changes) == 0 {
 		return nil
 	}
@@ -61,10 +144,34 @@ func (s *StateUpdate[T]) ByPK(id int64) error {
 	if pkField == nil {
 		return model.ErrNoPrimaryKey
 	}
-	s.builder.core.Where = Equals(pkField, id)
+	key := Equals(pkField, id)
+	s.builder.core.Where = key
+	if err := s.guardVersion(); err != nil {
+		return err
+	}
 	s.eventTopic = EventTopicUpdateByPK
-	s.eventIDs = []int64{id}
-	return s.Exec()
+	s.eventKeys = []any{id}
+	return s.execRow(key)
+}
+
+// ByKeys updates a single row by its full primary key, which may span several columns.
+// The keys are either one value per primary key column in struct field order,
+// or a single Dict keyed by column name. A versioned table needs the version read before, like ByPK.
+func (s *StateUpdate[T]) ByKeys(keys ...any) error {
+	if len(s.builder.Changes) == 0 {
+		return nil
+	}
+	cond, data, err := s.table.keysCondition(keys)
+	if err != nil {
+		return err
+	}
+	s.builder.core.Where = cond
+	if err := s.guardVersion(); err != nil {
+		return err
+	}
+	s.eventTopic = EventTopicUpdateByPK
+	s.eventKeys = []any{data}
+	return s.execRow(cond)
 }
 
 // OnTransaction sets the transaction for the UPDATE operation
@@ -154,9 +261,15 @@ func (s *StateUpdate[T]) Take(i int) *StateUpdate[T] {
 //	err := db.User.Update().Join(model.InnerJoin, *info, EqualsField(...)).Set(...).Exec()
 func (s *StateUpdate[T]) Join(joinType model.JoinType, info *TableInfo, on Condition) *StateUpdate[T] {
 	s.builder.Type = model.UpdateJoinQuery
-	s.builder.Joins = append(s.builder.Joins, &JoinTable{
-		JoinType: joinType, Table: info.Table(), On: Condition{},
-	})
+	jt := &JoinTable{JoinType: joinType, Table: info.Table(), On: Condition{}}
+	if s.table.db != nil && s.table.db.driver != nil {
+		var schema string
+		if jt.Table.Schema != nil {
+			schema = *jt.Table.Schema
+		}
+		jt.fullName = s.table.db.driver.FormatTableName(schema, jt.Table.Name)
+	}
+	s.builder.Joins = append(s.builder.Joins, jt)
 	return s.Filter(on)
 }
 
@@ -189,7 +302,8 @@ func (s *StateUpdate[T]) LeftJoin(fkey string, refer *Field) *StateUpdate[T] {
 //   - Provides the list of affected IDs for auditing
 //   - Uses InBatch to handle large ID sets within parameter limits
 //
-// Only works for tables with a single integer primary key.
+// Only works for tables with a single primary key. Exec returns int64 IDs,
+// use ExecKeys for string or UUID primary keys.
 //
 // Example:
 //
@@ -253,18 +367,25 @@ func (s *StateUpdateByID[T]) OnTransaction(tx model.Transaction) *StateUpdateByI
 // Exec executes the two-phase update.
 // Phase 1: SELECT pk FROM table WHERE <conditions> [LIMIT n]
 // Phase 2: UPDATE table SET ... WHERE pk IN (ids)  (batched via InBatch)
-// Returns the list of affected IDs.
+// Returns the list of affected IDs, or nil IDs when the primary key is not an integer.
 func (s *StateUpdateByID[T]) Exec() ([]int64, error) {
+	keys, err := s.ExecKeys()
+	return int64Keys(keys), err
+}
+
+// ExecKeys executes the two-phase update like Exec.
+// It returns the affected primary key values in their Go type, e.g. strings or UUIDs.
+func (s *StateUpdateByID[T]) ExecKeys() ([]any, error) {
 	if len(s.changes) == 0 {
 		return nil, fmt.Errorf("goent: StateUpdateByID.Exec has no changes, use Set() or SetMap()")
 	}
 
-	ids, cond, err := s.buildInBatchCond()
+	keys, cond, err := s.buildInBatchCond()
 	if err != nil {
 		return nil, err
 	}
-	if len(ids) == 0 {
-		return ids, nil
+	if len(keys) == 0 {
+		return keys, nil
 	}
 
 	state := NewStateWhere(s.ctx)
@@ -274,13 +395,14 @@ func (s *StateUpdateByID[T]) Exec() ([]int64, error) {
 
 	upd := &StateUpdate[T]{table: s.table, StateWhere: state, skipEvent: true}
 	upd.SetMap(s.changes)
+	s.table.touchChanges(upd.builder.Changes, false)
 	changes := changesToMap(upd.builder.Changes)
 	if err := upd.Exec(); err != nil {
-		return ids, err
+		return keys, err
 	}
-	// Send byid event with the queried IDs and changes
+	// Send byid event with the queried keys and changes
 	info := s.table.TableInfo
-	publishEvent(info.db.bus, info, s.conn, EventTopicUpdateByID,
-		s.where.Template, ids, changes, int64(len(ids)))
-	return ids, nil
+	publishKeysEvent(info.db.bus, info, s.conn, EventTopicUpdateByID,
+		s.where.Template, keys, changes, int64(len(keys)))
+	return keys, nil
 }
@@ -0,0 +1,205 @@
+package goent
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/azhai/goent/model"
+)
+
+// conflictClause is the ON CONFLICT clause of an INSERT, it is the same for PostgreSQL and SQLite.
+type conflictClause struct {
+	columns []string // conflict target columns, may be empty for DO NOTHING
+	nothing bool     // DO NOTHING instead of DO UPDATE
+	updates []string // columns set to their EXCLUDED value, nil means all inserted columns
+	exprs   []string // "column = expression" assignments from DoUpdateExpr
+
+	inserted []string // inserted columns which DO UPDATE sets by default
+}
+
+// write writes the clause to the buffer.
+func (c *conflictClause) write(buf *bytes.Buffer) {
+	buf.WriteString(" ON CONFLICT")
+	if len(c.columns) > 0 {
+		buf.WriteString(" (")
+		buf.WriteString(strings.Join(c.columns, ", "))
+		buf.WriteByte(')')
+	}
+	sets := c.assignments()
+	if c.nothing || len(sets) == 0 {
+		buf.WriteString(" DO NOTHING")
+		return
+	}
+	buf.WriteString(" DO UPDATE SET ")
+	buf.WriteString(strings.Join(sets, ", "))
+}
+
+// assignments returns the SET assignments of DO UPDATE.
+func (c *conflictClause) assignments() []string {
+	updates := c.updates
+	if updates == nil && len(c.exprs) == 0 {
+		updates = c.inserted
+	}
+	sets := make([]string, 0, len(updates)+len(c.exprs))
+	for _, name := range updates {
+		if slices.Contains(c.columns, name) {
+			continue
+		}
+		sets = append(sets, name+" = EXCLUDED."+name)
+	}
+	return append(sets, c.exprs...)
+}
+
+// upsertColumns returns the inserted columns which DO UPDATE sets by default:
+// all but the primary keys and the auto_create_time columns, which keep the value of the existing row.
+func (info *TableInfo) upsertColumns(fields []*Field) []string {
+	names := make([]string, 0, len(fields))
+	for _, fld := range fields {
+		col := info.ColumnInfo(fld.ColumnName)
+		if col == nil || col.IsPK || info.isCreateTime(col) {
+			continue
+		}
+		names = append(names, col.ColumnName)
+	}
+	return names
+}
+
+// isCreateTime returns true if the column is tagged with auto_create_time.
+func (info *TableInfo) isCreateTime(col *Column) bool {
+	for _, at := range info.autoTimes {
+		if at.column == col && !at.onUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// columnNames resolves column names given as strings, *Field or Col, like Select does.
+func (info *TableInfo) columnNames(fields []any) []string {
+	names := make([]string, 0, len(fields))
+	for _, one := range fields {
+		if fld, ok := info.resolveField(one); ok {
+			names = append(names, fld.ColumnName)
+		}
+	}
+	return names
+}
+
+// OnConflict turns the INSERT into an upsert when a row with the same values of the fields exists.
+// Follow it with DoUpdate, DoUpdateExpr or DoNothing, without them it does nothing.
+// The fields are column names or *Field, and need a unique index or primary key on them.
+//
+// Example:
+//
+//	err := db.Product.Insert().OnConflict("sku").DoUpdate("name", "price").All(true, products)
+func (s *StateInsert[T]) OnConflict(fields ...any) *StateInsert[T] {
+	s.conflict = &conflictClause{columns: s.table.columnNames(fields), nothing: true}
+	return s
+}
+
+// DoUpdate updates the existing row with the values of the row being inserted,
+// i.e. SET field = EXCLUDED.field. Without fields it updates all the inserted columns,
+// except the primary key, the conflict columns and the auto_create_time columns.
+func (s *StateInsert[T]) DoUpdate(fields ...any) *StateInsert[T] {
+	if s.conflict == nil {
+		s.conflict = &conflictClause{}
+	}
+	s.conflict.nothing = false
+	if len(fields) > 0 {
+		s.conflict.updates = append(s.conflict.updates, s.table.columnNames(fields)...)
+	}
+	return s
+}
+
+// DoUpdateExpr sets a column of the existing row to a SQL expression.
+// The expression may use EXCLUDED.column for the value being inserted and
+// the table name for the existing row.
+//
+// Example:
+//
+//	err := db.Stock.Insert().OnConflict("sku").
+//	    DoUpdateExpr("quantity", "stock.quantity + EXCLUDED.quantity").One(stock)
+func (s *StateInsert[T]) DoUpdateExpr(field any, expr string) *StateInsert[T] {
+	if s.conflict == nil {
+		s.conflict = &conflictClause{}
+	}
+	s.conflict.nothing = false
+	for _, name := range s.table.columnNames([]any{field}) {
+		s.conflict.exprs = append(s.conflict.exprs, name+" = "+expr)
+	}
+	return s
+}
+
+// DoNothing keeps the existing row and skips the row being inserted.
+// The primary keys of the skipped rows are not filled.
+func (s *StateInsert[T]) DoNothing() *StateInsert[T] {
+	if s.conflict == nil {
+		s.conflict = &conflictClause{}
+	}
+	s.conflict.nothing = true
+	return s
+}
+
+// prepareUpsert adds the ON CONFLICT clause to the INSERT of the fields.
+// When the primary key is returned, the conflict columns are returned too, to match the returned rows.
+func (s *StateInsert[T]) prepareUpsert(fields []*Field) {
+	s.conflict.inserted = s.table.upsertColumns(fields)
+	s.builder.Conflict = s.conflict
+	if s.builder.Returning != "" && len(s.conflict.columns) > 0 {
+		s.builder.Returning += ", " + strings.Join(s.conflict.columns, ", ")
+	}
+}
+
+// upsertReturning runs an upsert with RETURNING and fills the primary keys of the rows.
+// The returned rows are matched to the data by the conflict columns, because skipped
+// rows return nothing and the order of the returned rows is not guaranteed.
+// Without conflict columns, the keys are only filled when every row is returned.
+// It returns the number of returned rows.
+func (s *StateInsert[T]) upsertReturning(qr model.Query, data []*T, pkFid int) (int64, error) {
+	conn, cfg := s.Prepare(s.table.TableInfo)
+	rows, err := qr.WrapQuery(s.ctx, conn, cfg)
+	if err != nil || rows == nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	info, cols := s.table.TableInfo, s.conflict.columns
+	var reg map[string][]*T
+	if len(cols) > 0 {
+		if reg, _, err = mapRowsByColumns(info, data, cols); err != nil {
+			return 0, err
+		}
+	}
+	var returned []reflect.Value
+	for rows.Next() {
+		ret := reflect.New(info.modelType).Elem()
+		dest := []any{ret.Field(pkFid).Addr().Interface()}
+		for _, name := range cols {
+			dest = append(dest, ret.Field(info.Columns[name].FieldId).Addr().Interface())
+		}
+		if qr.Err = rows.Scan(dest...); qr.Err != nil {
+			return 0, cfg.ErrorQueryHandler(s.ctx, qr)
+		}
+		returned = append(returned, ret)
+	}
+	if len(cols) == 0 {
+		if len(returned) == len(data) {
+			for i, ret := range returned {
+				reflect.ValueOf(data[i]).Elem().Field(pkFid).Set(ret.Field(pkFid))
+			}
+		}
+		return int64(len(returned)), rows.Err()
+	}
+	for _, ret := range returned {
+		key, _, ok := tupleKey(info, ret, cols)
+		if !ok {
+			continue
+		}
+		for _, row := range reg[key] {
+			reflect.ValueOf(row).Elem().Field(pkFid).Set(ret.Field(pkFid))
+		}
+	}
+	return int64(len(returned)), rows.Err()
+}
@@ -0,0 +1,66 @@
+package goent
+
+import (
+	"reflect"
+
+	"github.com/azhai/goent/model"
+)
+
+// setVersion marks the integer column tagged with goe:"version" as the optimistic lock column.
+func (info *TableInfo) setVersion(column *Column, typeOf reflect.Type) {
+	if isIntegerKind(typeOf.Kind()) {
+		info.version = column
+	}
+}
+
+// HasVersion returns true if the table has a column tagged with goe:"version".
+func (info *TableInfo) HasVersion() bool {
+	return info.version != nil
+}
+
+// lockVersion turns the version column of the changes into an optimistic lock.
+// The value in the changes is the version the caller has read, it is replaced with the next version.
+// It returns the condition matching the read version and that version,
+// ok is false when the table has no version column or the changes do not contain it.
+func (info *TableInfo) lockVersion(changes map[*Field]any) (cond Condition, current int64, ok bool) {
+	if info.version == nil {
+		return Condition{}, 0, false
+	}
+	for fld, val := range changes {
+		if fld.ColumnName != info.version.ColumnName || fld.TableAddr != info.TableAddr && fld.TableAddr != 0 {
+			continue
+		}
+		if current, ok = keyOf(val).(int64); !ok {
+			return Condition{}, 0, false
+		}
+		changes[fld] = current + 1
+		field := &Field{TableAddr: info.TableAddr, ColumnName: info.version.ColumnName, FieldId: info.version.FieldId}
+		return Equals(field, current), current, true
+	}
+	return Condition{}, 0, false
+}
+
+// touchVersion increments the version column in the database when the changes of an update do not set it,
+// so that any update of a row makes the version read by other editors stale.
+func (info *TableInfo) touchVersion(changes map[*Field]any) {
+	if info.version == nil || len(changes) == 0 || hasChange(changes, info.TableAddr, info.version.ColumnName) {
+		return
+	}
+	fld := info.sortedFields[info.version.FieldId]
+	changes[fld] = incrExpr(fld, 1)
+}
+
+// bumpVersion sets the version field of a row to the next version after a successful update.
+func (info *TableInfo) bumpVersion(valueOf reflect.Value, current int64) {
+	fieldOf := valueOf.Field(info.version.FieldId)
+	if fieldOf.CanInt() {
+		fieldOf.SetInt(current + 1)
+	} else if fieldOf.CanUint() {
+		fieldOf.SetUint(uint64(current + 1))
+	}
+}
+
+// staleError returns the error of an update which did not find the row with the read version.
+func (info *TableInfo) staleError(current int64) error {
+	return model.NewStaleObjectError(info.TableName, current)
+}
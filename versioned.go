@@ -0,0 +1,408 @@
+package goent
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azhai/goent/model"
+)
+
+// MigrationTable is the bookkeeping table that records applied versions
+const MigrationTable = "goent_migrations"
+
+// NoTransactionMarker disables the wrapping transaction when found in a SQL migration file
+// SQLite table rebuilds already contain their own BEGIN/COMMIT, so generated files carry it
+const NoTransactionMarker = "-- goent:no-transaction"
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// MigrationFunc applies or reverts one migration step in Go code
+// The tx is nil when the migration is marked NoTx
+type MigrationFunc func(ctx context.Context, tx model.Transaction) error
+
+// Migration is a numbered schema change with its up and down steps
+// Each step is either raw SQL or a Go function, the function wins when both are set
+type Migration struct {
+	Version  int64         // Unique, ascending version number
+	Name     string        // Short description, used in file names
+	UpSql    string        // SQL that applies the change
+	DownSql  string        // SQL that reverts the change
+	UpFunc   MigrationFunc // Go function that applies the change
+	DownFunc MigrationFunc // Go function that reverts the change
+	NoTx     bool          // Run outside of a transaction
+}
+
+// MigrationStatus describes whether a registered migration has been applied
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Missing   bool // Recorded in the database but not registered
+}
+
+// Migrations keeps the versioned migrations registered with a DB
+// It is obtained from [DB.Migrations]
+type Migrations struct {
+	db    *DB
+	items []*Migration
+	lock  sync.Mutex
+}
+
+// Migrations returns the versioned migration registry of the database
+// It is created on first use and shared by later calls
+//
+// Example:
+//
+//	migs := db.Migrations()
+//	_ = migs.LoadFiles(os.DirFS("migrations"))
+//	n, err := migs.Up(ctx)
+func (db *DB) Migrations() *Migrations {
+	db.migrateOnce.Do(func() {
+		db.migrations = &Migrations{db: db}
+	})
+	return db.migrations
+}
+
+// Register adds migrations to the registry
+// It returns ErrMigrationVersion when a version is not positive or already registered
+func (ms *Migrations) Register(migs ...*Migration) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	for _, m := range migs {
+		if m.Version <= 0 || ms.find(m.Version) != nil {
+			return model.NewMigrationError(m.Version, m.Name, model.ErrMigrationVersion)
+		}
+		ms.items = append(ms.items, m)
+	}
+	slices.SortFunc(ms.items, func(a, b *Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return nil
+}
+
+// LoadFiles registers the SQL migrations found in fsys
+// File names follow the pattern <version>_<name>.up.sql and <version>_<name>.down.sql
+//
+// Example:
+//
+//	err := db.Migrations().LoadFiles(os.DirFS("migrations"))
+func (ms *Migrations) LoadFiles(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	found := make(map[int64]*Migration)
+	for _, entry := range entries {
+		parts := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || parts == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(parts[1], 10, 64)
+		m := found[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: parts[2]}
+			found[version] = m
+		} else if m.Name != parts[2] {
+			return model.NewMigrationError(version, parts[2], model.ErrMigrationVersion)
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+		content := string(data)
+		if strings.Contains(content, NoTransactionMarker) {
+			m.NoTx = true
+		}
+		if parts[3] == "up" {
+			m.UpSql = content
+		} else {
+			m.DownSql = content
+		}
+	}
+	migs := make([]*Migration, 0, len(found))
+	for _, m := range found {
+		migs = append(migs, m)
+	}
+	return ms.Register(migs...)
+}
+
+// List returns the registered migrations ordered by version
+func (ms *Migrations) List() []*Migration {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return slices.Clone(ms.items)
+}
+
+// Up applies all pending migrations in version order
+// It returns the number of migrations applied before any error
+func (ms *Migrations) Up(ctx context.Context) (int, error) {
+	applied, err := ms.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, m := range ms.List() {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err = ms.run(ctx, m, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down reverts the n most recently applied migrations in reverse version order
+// It returns ErrMigrationNotFound when an applied version is no longer registered
+func (ms *Migrations) Down(ctx context.Context, n int) (int, error) {
+	versions, err := ms.appliedVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := len(versions) - 1; i >= 0 && count < n; i-- {
+		m := ms.lookup(versions[i])
+		if m == nil {
+			return count, model.NewMigrationError(versions[i], "", model.ErrMigrationNotFound)
+		}
+		if err = ms.run(ctx, m, false); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Redo reverts the latest applied migration and applies it again
+// It does nothing when no migration has been applied
+func (ms *Migrations) Redo(ctx context.Context) error {
+	versions, err := ms.appliedVersions(ctx)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+	last := versions[len(versions)-1]
+	m := ms.lookup(last)
+	if m == nil {
+		return model.NewMigrationError(last, "", model.ErrMigrationNotFound)
+	}
+	if err = ms.run(ctx, m, false); err != nil {
+		return err
+	}
+	return ms.run(ctx, m, true)
+}
+
+// Status reports every registered migration and whether it has been applied
+// Versions recorded in the database without a registered migration are marked Missing
+func (ms *Migrations) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := ms.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []MigrationStatus
+	for _, m := range ms.List() {
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			st.Applied, st.AppliedAt = true, &at
+			delete(applied, m.Version)
+		}
+		result = append(result, st)
+	}
+	for version, at := range applied {
+		result = append(result, MigrationStatus{
+			Version: version, Applied: true, AppliedAt: &at, Missing: true,
+		})
+	}
+	slices.SortFunc(result, func(a, b MigrationStatus) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return result, nil
+}
+
+// find returns the registered migration with the version, the caller holds the lock
+func (ms *Migrations) find(version int64) *Migration {
+	for _, m := range ms.items {
+		if m.Version == version {
+			return m
+		}
+	}
+	return nil
+}
+
+func (ms *Migrations) lookup(version int64) *Migration {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return ms.find(version)
+}
+
+// ensureTable creates the bookkeeping table when it does not exist yet
+func (ms *Migrations) ensureTable(ctx context.Context) error {
+	return ms.db.RawExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+MigrationTable+
+		" (version BIGINT NOT NULL PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)")
+}
+
+// applied returns the applied versions with their timestamps
+func (ms *Migrations) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if err := ms.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := ms.db.RawQueryContext(ctx, "SELECT version, applied_at FROM "+MigrationTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at sql.NullTime
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		result[version] = at.Time
+	}
+	return result, rows.Err()
+}
+
+// appliedVersions returns the applied versions in ascending order
+func (ms *Migrations) appliedVersions(ctx context.Context) ([]int64, error) {
+	applied, err := ms.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// run applies (up) or reverts (down) one migration and updates the bookkeeping table
+func (ms *Migrations) run(ctx context.Context, m *Migration, up bool) error {
+	step, rawSql := m.DownFunc, m.DownSql
+	record := model.CreateQuery("DELETE FROM "+MigrationTable+" WHERE version = $1", []any{m.Version})
+	if up {
+		step, rawSql = m.UpFunc, m.UpSql
+		record = model.CreateQuery("INSERT INTO "+MigrationTable+" (version, name, applied_at) VALUES ($1, $2, $3)",
+			[]any{m.Version, m.Name, time.Now()})
+	}
+	dc := ms.db.driver.GetDatabaseConfig()
+	exec := func(conn model.Connection, tx model.Transaction) error {
+		if step != nil {
+			if err := step(ctx, tx); err != nil {
+				return err
+			}
+		} else if strings.TrimSpace(rawSql) != "" {
+			query := model.CreateQuery(ms.db.driver.NormalizeSql(rawSql), nil)
+			if err := query.WrapExec(ctx, conn, dc); err != nil {
+				return err
+			}
+		}
+		return record.WrapExec(ctx, conn, dc)
+	}
+
+	var err error
+	if m.NoTx {
+		err = exec(ms.db.driver.NewConnection(), nil)
+	} else {
+		err = ms.db.BeginTransactionContext(ctx, sql.LevelDefault, func(tx model.Transaction) error {
+			return exec(tx, tx)
+		})
+	}
+	if err != nil {
+		return model.NewMigrationError(m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// GenerateMigration writes the DDL that AutoMigrate would run as a new numbered migration in dir
+// The down file drops the tables the plan creates, other changes must be reverted by hand
+// It returns the path of the up file, or an empty string when the schema is already up to date
+//
+// Example:
+//
+//	path, err := goent.GenerateMigration(ctx, db, "migrations", "add_users")
+func GenerateMigration(ctx context.Context, ent any, dir, name string) (string, error) {
+	db := getDatabase(ent)
+	mig := migrateFrom(ent, db)
+	if mig.Error != nil {
+		return "", mig.Error
+	}
+	mig.Plan = new(model.MigrationPlan)
+	if err := db.driver.MigrateContext(ctx, mig.Migrator); err != nil {
+		return "", err
+	}
+	if mig.Plan.IsEmpty() {
+		return "", nil
+	}
+
+	header := ""
+	if strings.Contains(mig.Plan.Sql, "BEGIN TRANSACTION") {
+		header = NoTransactionMarker + "\n"
+	}
+	downSql := new(strings.Builder)
+	downSql.WriteString(header)
+	cascade := ""
+	if db.DriverName() == "PostgreSQL" {
+		cascade = " CASCADE"
+	}
+	escaped := make(map[string]string, len(mig.Tables))
+	for _, t := range mig.Tables {
+		escaped[t.PlanName()] = t.EscapingTableName()
+	}
+	// drop in reverse creation order so referencing tables go first
+	for i := len(mig.Plan.CreatedTables) - 1; i >= 0; i-- {
+		name := escaped[mig.Plan.CreatedTables[i]]
+		downSql.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %s%s;\n", name, cascade))
+	}
+
+	return WriteMigrationFiles(dir, name, header+mig.Plan.Sql, downSql.String())
+}
+
+// WriteMigrationFiles writes a new numbered pair of up and down SQL files in dir
+// The version is one above the highest version found in dir, it returns the path of the up file
+func WriteMigrationFiles(dir, name, upSql, downSql string) (string, error) {
+	version, err := nextMigrationVersion(dir)
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	prefix := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	if err = os.WriteFile(prefix+".up.sql", []byte(upSql), 0o644); err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(prefix+".down.sql", []byte(downSql), 0o644); err != nil {
+		return "", err
+	}
+	return prefix + ".up.sql", nil
+}
+
+// nextMigrationVersion returns one above the highest migration version found in dir
+func nextMigrationVersion(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	var last int64
+	for _, entry := range entries {
+		if parts := migrationFileRe.FindStringSubmatch(entry.Name()); parts != nil {
+			version, _ := strconv.ParseInt(parts[1], 10, 64)
+			last = max(last, version)
+		}
+	}
+	return last + 1, nil
+}
@@ -0,0 +1,174 @@
+package goent
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Frame bounds of a window frame, see Window.Rows and Window.Range.
+const (
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	CurrentRow         = "CURRENT ROW"
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+)
+
+// Preceding returns the frame bound n rows before the current row.
+func Preceding(n int) string {
+	return strconv.Itoa(n) + " PRECEDING"
+}
+
+// Following returns the frame bound n rows after the current row.
+func Following(n int) string {
+	return strconv.Itoa(n) + " FOLLOWING"
+}
+
+// Window is the OVER clause of a window function: the partitions, their order and the frame.
+type Window struct {
+	partitions []*Field
+	orders     []*Order
+	frame      string
+}
+
+// PartitionBy creates a window partitioned by the fields.
+//
+// Example:
+//
+//	w := goent.PartitionBy(db.Animal.Field("habitat_id")).OrderByDesc(db.Animal.Field("age"))
+//	rank := goent.RowNumber().Over(w).As("rank")
+func PartitionBy(fields ...*Field) *Window {
+	return new(Window).PartitionBy(fields...)
+}
+
+// OrderedBy creates a window over all the rows, ordered by the fields.
+func OrderedBy(fields ...*Field) *Window {
+	return new(Window).OrderBy(fields...)
+}
+
+// PartitionBy adds fields to the PARTITION BY of the window.
+func (w *Window) PartitionBy(fields ...*Field) *Window {
+	w.partitions = append(w.partitions, fields...)
+	return w
+}
+
+// OrderBy adds fields to the ORDER BY of the window, in ascending order.
+func (w *Window) OrderBy(fields ...*Field) *Window {
+	for _, fld := range fields {
+		w.orders = append(w.orders, &Order{Field: fld})
+	}
+	return w
+}
+
+// OrderByDesc adds fields to the ORDER BY of the window, in descending order.
+func (w *Window) OrderByDesc(fields ...*Field) *Window {
+	for _, fld := range fields {
+		w.orders = append(w.orders, &Order{Field: fld, Desc: true})
+	}
+	return w
+}
+
+// Rows sets the frame of the window to the rows between the bounds.
+//
+// Example:
+//
+//	w := goent.OrderedBy(db.Sale.Field("day")).Rows(goent.UnboundedPreceding, goent.CurrentRow)
+//	total := db.Sale.Field("amount").Func("SUM(%s)", "running_total").Over(w)
+func (w *Window) Rows(start, end string) *Window {
+	w.frame = "ROWS BETWEEN " + start + " AND " + end
+	return w
+}
+
+// Range sets the frame of the window to the rows whose order values are between the bounds.
+func (w *Window) Range(start, end string) *Window {
+	w.frame = "RANGE BETWEEN " + start + " AND " + end
+	return w
+}
+
+// over returns the OVER clause, with qualified column names unless simple is true.
+func (w *Window) over(simple bool) string {
+	name := func(f *Field) string {
+		if simple {
+			return f.Simple()
+		}
+		return f.String()
+	}
+	var sb strings.Builder
+	sb.WriteString(" OVER (")
+	if len(w.partitions) > 0 {
+		sb.WriteString("PARTITION BY ")
+		for i, fld := range w.partitions {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(name(fld))
+		}
+	}
+	if len(w.orders) > 0 {
+		if len(w.partitions) > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString("ORDER BY ")
+		for i, ord := range w.orders {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(name(ord.Field))
+			if ord.Desc {
+				sb.WriteString(" DESC")
+			}
+		}
+	}
+	if w.frame != "" {
+		if len(w.partitions) > 0 || len(w.orders) > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(w.frame)
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// Over turns the function of the field into a window function computed over the window,
+// e.g. a running SUM, instead of an aggregate. Use As to name the result column.
+func (f *Field) Over(w *Window) *Field {
+	fld := *f
+	fld.FieldId, fld.window = -1, w
+	return &fld
+}
+
+// As returns the field with an alias, written as "AS alias" in the selected columns.
+// The alias names the column of a CTE or subquery built from the query.
+func (f *Field) As(alias string) *Field {
+	fld := *f
+	fld.AliasName = alias
+	return &fld
+}
+
+// RowNumber is the ROW_NUMBER() window function, the number of the row in its partition from 1.
+// It must be followed by Over.
+func RowNumber() *Field {
+	return &Field{FieldId: -1, Function: "ROW_NUMBER(%s)"}
+}
+
+// Rank is the RANK() window function, the rank of the row in its partition with gaps for ties.
+// It must be followed by Over.
+func Rank() *Field {
+	return &Field{FieldId: -1, Function: "RANK(%s)"}
+}
+
+// DenseRank is the DENSE_RANK() window function, the rank of the row in its partition without gaps.
+// It must be followed by Over.
+func DenseRank() *Field {
+	return &Field{FieldId: -1, Function: "DENSE_RANK(%s)"}
+}
+
+// Lag is the LAG() window function, the value of the field offset rows before the current row,
+// NULL if there is no such row. It must be followed by Over.
+func Lag(field *Field, offset int) *Field {
+	return field.Func("LAG(%s, "+strconv.Itoa(offset)+")", "")
+}
+
+// Lead is the LEAD() window function, the value of the field offset rows after the current row,
+// NULL if there is no such row. It must be followed by Over.
+func Lead(field *Field, offset int) *Field {
+	return field.Func("LEAD(%s, "+strconv.Itoa(offset)+")", "")
+}